@@ -6,11 +6,19 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/config"
 	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/container"
 )
 
+const (
+	// shutdownTimeout bounds waiting for in-flight HTTP requests
+	shutdownTimeout = 10 * time.Second
+	// jobsShutdownTimeout bounds waiting for running background jobs
+	jobsShutdownTimeout = 30 * time.Second
+)
+
 func maskSecret(secret string) string {
 	if len(secret) <= 4 {
 		return "****"
@@ -36,6 +44,7 @@ func main() {
 	fmt.Printf("VK: App ID: %d, Secret Key: %s\n", cfg.VK.AppID, maskSecret(cfg.VK.SecretKey))
 	fmt.Printf("Storage: %s (%s)\n", cfg.Storage.Type, cfg.Storage.Path)
 	fmt.Printf("Log Level: %s\n", cfg.Logging.Level)
+	fmt.Printf("Dev Auth: %t\n", cfg.Server.DevAuthEnabled())
 	fmt.Printf("====================\n\n")
 
 	// Initialize dependency injection container
@@ -69,12 +78,20 @@ func main() {
 	<-quit
 
 	// Graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 10)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
 	if err := app.Server.Shutdown(ctx); err != nil {
 		fmt.Printf("Server shutdown error: %v\n", err)
-		os.Exit(1)
+	}
+
+	// Let running background jobs finish, queued ones run after the next start.
+	// The drain gets its own timeout, the server may have used up the first one
+	jobsCtx, cancelJobs := context.WithTimeout(context.Background(), jobsShutdownTimeout)
+	defer cancelJobs()
+
+	if err := app.Jobs.Shutdown(jobsCtx); err != nil {
+		fmt.Printf("Jobs shutdown error: %v\n", err)
 	}
 
 	fmt.Println("Server exited properly")
@@ -2,20 +2,27 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server         ServerConfig
-	Database       DatabaseConfig
-	Redis          RedisConfig
-	JWT            JWTConfig
-	Encryption     EncryptionConfig
-	Storage        StorageConfig
-	Logging        LoggingConfig
+	Server       ServerConfig
+	Database     DatabaseConfig
+	Redis        RedisConfig
+	JWT          JWTConfig
+	VK           VKConfig
+	Encryption   EncryptionConfig
+	Storage      StorageConfig
+	Logging      LoggingConfig
+	Feed         FeedConfig
+	Swipe        SwipeConfig
+	Jobs         JobsConfig
+	AI           AIConfig
 	GeminiAPIKey string
+}
 
 type ServerConfig struct {
 	Host         string
@@ -46,11 +53,21 @@ type JWTConfig struct {
 	RefreshSecret    string
 	AccessExpiryMin  int
 	RefreshExpiryDay int
+	// SessionCleanupInterval is how often expired sessions are deleted
+	SessionCleanupInterval time.Duration
 }
 
 type VKConfig struct {
 	SecretKey string
 	AppID     int
+	// ServiceToken is the app service token used for push notifications, push is disabled without it
+	ServiceToken string
+	// APIBaseURL overrides the VK API endpoint (e.g. a local fake server)
+	APIBaseURL string
+	// VerifySignature enables launch params signature verification
+	VerifySignature bool
+	// LaunchParamsMaxAge rejects launch params signed earlier (by vk_ts)
+	LaunchParamsMaxAge time.Duration
 }
 
 type EncryptionConfig struct {
@@ -66,6 +83,82 @@ type LoggingConfig struct {
 	Level string
 }
 
+type SwipeConfig struct {
+	// UndoWindow is how long after a swipe it can be undone
+	UndoWindow time.Duration
+	// UndoDailyLimit is the max number of undone swipes per user in 24 hours, 0 disables undo
+	UndoDailyLimit int
+	// SuperLikeDailyLimit is the max number of super likes per user in 24 hours, 0 disables them
+	SuperLikeDailyLimit int
+	// RatePerMinute is the max number of swipes per user per minute, 0 means unlimited
+	RatePerMinute int
+	// LikesPerDay is the max number of likes per user per day (UTC), 0 means unlimited
+	LikesPerDay int
+	// Users with at least MassLikerMinSwipes swipes in MassLikerWindow and a like ratio
+	// of at least MassLikerRatio are demoted in the feed, flags are recalculated every MassLikerInterval
+	MassLikerWindow    time.Duration
+	MassLikerMinSwipes int
+	MassLikerRatio     float64
+	MassLikerInterval  time.Duration
+}
+
+// JobsConfig configures background job workers
+type JobsConfig struct {
+	// Workers is the number of jobs run concurrently by one instance
+	Workers      int
+	PollInterval time.Duration
+	// MaxAttempts is the number of runs after which a failing job is moved to the dead letter state
+	MaxAttempts int
+	// Retries are delayed by BackoffBase doubled on every attempt, up to BackoffMax
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// Timeout limits a single run of a job
+	Timeout time.Duration
+}
+
+// AI Wingman providers
+const (
+	AIProviderGemini = "gemini"
+	AIProviderOpenAI = "openai"
+	AIProviderFake   = "fake"
+)
+
+// AIConfig configures the AI Wingman language model
+type AIConfig struct {
+	// Provider is gemini, openai (any OpenAI-compatible API) or fake (templates, no model).
+	// Defaults to gemini if GEMINI_API_KEY is set and to fake otherwise.
+	Provider string
+	// Model is the model name, empty means the provider default (gemini only)
+	Model       string
+	Temperature float64
+	// Timeout limits a single request to the model
+	Timeout time.Duration
+	// BaseURL and APIKey of the OpenAI-compatible API
+	BaseURL string
+	APIKey  string
+	// PromptVersions are the prompt versions in use, matches and users are split between them evenly
+	PromptVersions []string
+	// CacheTTL is how long generated texts are reused for the same inputs, zero disables the cache
+	CacheTTL time.Duration
+	// DailyBudget and UserDailyBudget limit model calls per day in total and per user, zero means no limit
+	DailyBudget     int
+	UserDailyBudget int
+	// After BreakerThreshold consecutive failures template texts are used for BreakerCooldown
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// FeedConfig holds weights of feed score components, a zero weight disables the component
+type FeedConfig struct {
+	PersonalityWeight float64
+	InterestsWeight   float64
+	DistanceWeight    float64
+	ActivityWeight    float64
+	EmbeddingWeight   float64
+	// ActivityHalfLife is the time offline after which the activity score halves
+	ActivityHalfLife time.Duration
+}
+
 // Load loads configuration from environment variables or .env file
 func Load() (*Config, error) {
 	viper.SetConfigFile(".env")
@@ -74,6 +167,42 @@ func Load() (*Config, error) {
 	// Try to read from .env file, but don't fail if it doesn't exist
 	_ = viper.ReadInConfig()
 
+	// Defaults for optional settings
+	viper.SetDefault("JWT_ACCESS_EXPIRY_MIN", 15)
+	viper.SetDefault("JWT_REFRESH_EXPIRY_DAY", 30)
+	viper.SetDefault("SESSION_CLEANUP_INTERVAL", time.Hour)
+	viper.SetDefault("VK_VERIFY_SIGNATURE", true)
+	viper.SetDefault("VK_LAUNCH_PARAMS_MAX_AGE", time.Hour)
+	viper.SetDefault("FEED_WEIGHT_PERSONALITY", 40)
+	viper.SetDefault("FEED_WEIGHT_INTERESTS", 30)
+	viper.SetDefault("FEED_WEIGHT_DISTANCE", 30)
+	viper.SetDefault("FEED_WEIGHT_ACTIVITY", 10)
+	viper.SetDefault("FEED_WEIGHT_EMBEDDING", 0)
+	viper.SetDefault("FEED_ACTIVITY_HALF_LIFE", 72*time.Hour)
+	viper.SetDefault("SWIPE_UNDO_WINDOW", 10*time.Minute)
+	viper.SetDefault("SWIPE_UNDO_DAILY_LIMIT", 3)
+	viper.SetDefault("SWIPE_SUPER_LIKE_DAILY_LIMIT", 1)
+	viper.SetDefault("SWIPE_RATE_PER_MINUTE", 60)
+	viper.SetDefault("SWIPE_LIKES_PER_DAY", 100)
+	viper.SetDefault("SWIPE_MASS_LIKER_WINDOW", 7*24*time.Hour)
+	viper.SetDefault("SWIPE_MASS_LIKER_MIN_SWIPES", 100)
+	viper.SetDefault("SWIPE_MASS_LIKER_RATIO", 0.95)
+	viper.SetDefault("SWIPE_MASS_LIKER_INTERVAL", time.Hour)
+	viper.SetDefault("JOBS_WORKERS", 4)
+	viper.SetDefault("JOBS_POLL_INTERVAL", time.Second)
+	viper.SetDefault("JOBS_MAX_ATTEMPTS", 5)
+	viper.SetDefault("JOBS_BACKOFF_BASE", 5*time.Second)
+	viper.SetDefault("JOBS_BACKOFF_MAX", 10*time.Minute)
+	viper.SetDefault("JOBS_TIMEOUT", 2*time.Minute)
+	viper.SetDefault("AI_TEMPERATURE", 0.7)
+	viper.SetDefault("AI_TIMEOUT", 30*time.Second)
+	viper.SetDefault("AI_PROMPT_VERSIONS", "v1")
+	viper.SetDefault("AI_CACHE_TTL", 7*24*time.Hour)
+	viper.SetDefault("AI_DAILY_BUDGET", 5000)
+	viper.SetDefault("AI_USER_DAILY_BUDGET", 50)
+	viper.SetDefault("AI_BREAKER_THRESHOLD", 5)
+	viper.SetDefault("AI_BREAKER_COOLDOWN", time.Minute)
+
 	config := &Config{
 		Server: ServerConfig{
 			Host:         viper.GetString("SERVER_HOST"),
@@ -97,14 +226,19 @@ func Load() (*Config, error) {
 			DB:       viper.GetInt("REDIS_DB"),
 		},
 		JWT: JWTConfig{
-			AccessSecret:     viper.GetString("JWT_ACCESS_SECRET"),
-			RefreshSecret:    viper.GetString("JWT_REFRESH_SECRET"),
-			AccessExpiryMin:  viper.GetInt("JWT_ACCESS_EXPIRY_MIN"),
-			RefreshExpiryDay: viper.GetInt("JWT_REFRESH_EXPIRY_DAY"),
+			AccessSecret:           viper.GetString("JWT_ACCESS_SECRET"),
+			RefreshSecret:          viper.GetString("JWT_REFRESH_SECRET"),
+			AccessExpiryMin:        viper.GetInt("JWT_ACCESS_EXPIRY_MIN"),
+			RefreshExpiryDay:       viper.GetInt("JWT_REFRESH_EXPIRY_DAY"),
+			SessionCleanupInterval: viper.GetDuration("SESSION_CLEANUP_INTERVAL"),
 		},
 		VK: VKConfig{
-			SecretKey: viper.GetString("VK_SECRET_KEY"),
-			AppID:     viper.GetInt("VK_APP_ID"),
+			SecretKey:          viper.GetString("VK_SECRET_KEY"),
+			AppID:              viper.GetInt("VK_APP_ID"),
+			ServiceToken:       viper.GetString("VK_SERVICE_TOKEN"),
+			APIBaseURL:         viper.GetString("VK_API_BASE_URL"),
+			VerifySignature:    viper.GetBool("VK_VERIFY_SIGNATURE"),
+			LaunchParamsMaxAge: viper.GetDuration("VK_LAUNCH_PARAMS_MAX_AGE"),
 		},
 		Encryption: EncryptionConfig{
 			AESKey: viper.GetString("AES_ENCRYPTION_KEY"),
@@ -116,9 +250,58 @@ func Load() (*Config, error) {
 		Logging: LoggingConfig{
 			Level: viper.GetString("LOG_LEVEL"),
 		},
+		Feed: FeedConfig{
+			PersonalityWeight: viper.GetFloat64("FEED_WEIGHT_PERSONALITY"),
+			InterestsWeight:   viper.GetFloat64("FEED_WEIGHT_INTERESTS"),
+			DistanceWeight:    viper.GetFloat64("FEED_WEIGHT_DISTANCE"),
+			ActivityWeight:    viper.GetFloat64("FEED_WEIGHT_ACTIVITY"),
+			EmbeddingWeight:   viper.GetFloat64("FEED_WEIGHT_EMBEDDING"),
+			ActivityHalfLife:  viper.GetDuration("FEED_ACTIVITY_HALF_LIFE"),
+		},
+		Swipe: SwipeConfig{
+			UndoWindow:          viper.GetDuration("SWIPE_UNDO_WINDOW"),
+			UndoDailyLimit:      viper.GetInt("SWIPE_UNDO_DAILY_LIMIT"),
+			SuperLikeDailyLimit: viper.GetInt("SWIPE_SUPER_LIKE_DAILY_LIMIT"),
+			RatePerMinute:       viper.GetInt("SWIPE_RATE_PER_MINUTE"),
+			LikesPerDay:         viper.GetInt("SWIPE_LIKES_PER_DAY"),
+			MassLikerWindow:     viper.GetDuration("SWIPE_MASS_LIKER_WINDOW"),
+			MassLikerMinSwipes:  viper.GetInt("SWIPE_MASS_LIKER_MIN_SWIPES"),
+			MassLikerRatio:      viper.GetFloat64("SWIPE_MASS_LIKER_RATIO"),
+			MassLikerInterval:   viper.GetDuration("SWIPE_MASS_LIKER_INTERVAL"),
+		},
+		Jobs: JobsConfig{
+			Workers:      viper.GetInt("JOBS_WORKERS"),
+			PollInterval: viper.GetDuration("JOBS_POLL_INTERVAL"),
+			MaxAttempts:  viper.GetInt("JOBS_MAX_ATTEMPTS"),
+			BackoffBase:  viper.GetDuration("JOBS_BACKOFF_BASE"),
+			BackoffMax:   viper.GetDuration("JOBS_BACKOFF_MAX"),
+			Timeout:      viper.GetDuration("JOBS_TIMEOUT"),
+		},
+		AI: AIConfig{
+			Provider:    viper.GetString("AI_PROVIDER"),
+			Model:       viper.GetString("AI_MODEL"),
+			Temperature: viper.GetFloat64("AI_TEMPERATURE"),
+			Timeout:     viper.GetDuration("AI_TIMEOUT"),
+			BaseURL:     viper.GetString("AI_BASE_URL"),
+			APIKey:      viper.GetString("AI_API_KEY"),
+			// Comma-separated, e.g. "v1,v2" to A/B test prompts
+			PromptVersions:   splitList(viper.GetString("AI_PROMPT_VERSIONS")),
+			CacheTTL:         viper.GetDuration("AI_CACHE_TTL"),
+			DailyBudget:      viper.GetInt("AI_DAILY_BUDGET"),
+			UserDailyBudget:  viper.GetInt("AI_USER_DAILY_BUDGET"),
+			BreakerThreshold: viper.GetInt("AI_BREAKER_THRESHOLD"),
+			BreakerCooldown:  viper.GetDuration("AI_BREAKER_COOLDOWN"),
+		},
 		GeminiAPIKey: viper.GetString("GEMINI_API_KEY"),
 	}
 
+	if config.AI.Provider == "" {
+		config.AI.Provider = AIProviderFake
+		if config.GeminiAPIKey != "" {
+			config.AI.Provider = AIProviderGemini
+		}
+	}
+
 	// Validate critical configuration
 	if err := config.Validate(); err != nil {
 		return nil, err
@@ -147,6 +330,12 @@ func (c *Config) Validate() error {
 	if c.JWT.RefreshSecret == "" {
 		return fmt.Errorf("JWT refresh secret is required")
 	}
+	if c.JWT.RefreshSecret == c.JWT.AccessSecret {
+		return fmt.Errorf("JWT refresh secret must differ from access secret")
+	}
+	if c.JWT.AccessExpiryMin <= 0 || c.JWT.RefreshExpiryDay <= 0 {
+		return fmt.Errorf("JWT token expiry must be positive")
+	}
 	if len(c.Encryption.AESKey) != 32 {
 		return fmt.Errorf("AES encryption key must be exactly 32 characters for AES-256")
 	}
@@ -156,9 +345,112 @@ func (c *Config) Validate() error {
 	if len(c.VK.SecretKey) < 16 {
 		return fmt.Errorf("VK secret key must be at least 16 characters")
 	}
+	if !c.VK.VerifySignature && c.Server.Env == "production" {
+		return fmt.Errorf("VK signature verification can't be disabled in production")
+	}
+	if err := c.Feed.Validate(); err != nil {
+		return err
+	}
+	if err := c.Swipe.Validate(); err != nil {
+		return err
+	}
+	if err := c.Jobs.Validate(); err != nil {
+		return err
+	}
+	if err := c.AI.Validate(); err != nil {
+		return err
+	}
+	if c.AI.Provider == AIProviderGemini && c.GeminiAPIKey == "" {
+		return fmt.Errorf("GEMINI_API_KEY is required for the gemini AI provider")
+	}
 	return nil
 }
 
+// Validate checks the AI Wingman provider settings
+func (c *AIConfig) Validate() error {
+	switch c.Provider {
+	case AIProviderGemini, AIProviderFake:
+	case AIProviderOpenAI:
+		if c.BaseURL == "" || c.Model == "" {
+			return fmt.Errorf("AI base URL and model are required for the openai AI provider")
+		}
+	default:
+		return fmt.Errorf("unknown AI provider %q", c.Provider)
+	}
+	if c.Temperature < 0 || c.Temperature > 2 {
+		return fmt.Errorf("AI temperature must be in [0, 2]")
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("AI timeout must be positive")
+	}
+	if len(c.PromptVersions) == 0 {
+		return fmt.Errorf("at least one AI prompt version is required")
+	}
+	if c.CacheTTL < 0 || c.DailyBudget < 0 || c.UserDailyBudget < 0 || c.BreakerThreshold < 0 {
+		return fmt.Errorf("AI cache TTL, budgets and breaker threshold must not be negative")
+	}
+	if c.BreakerThreshold > 0 && c.BreakerCooldown <= 0 {
+		return fmt.Errorf("AI breaker cooldown must be positive")
+	}
+	return nil
+}
+
+// Validate checks job worker settings
+func (c *JobsConfig) Validate() error {
+	if c.Workers <= 0 || c.MaxAttempts <= 0 {
+		return fmt.Errorf("job workers and max attempts must be positive")
+	}
+	if c.PollInterval <= 0 || c.BackoffBase <= 0 || c.Timeout <= 0 {
+		return fmt.Errorf("job poll interval, backoff and timeout must be positive")
+	}
+	if c.BackoffMax < c.BackoffBase {
+		return fmt.Errorf("job max backoff can't be less than base backoff")
+	}
+	return nil
+}
+
+// Validate checks swipe limits
+func (c *SwipeConfig) Validate() error {
+	if c.UndoWindow < 0 || c.UndoDailyLimit < 0 || c.SuperLikeDailyLimit < 0 {
+		return fmt.Errorf("swipe undo window and daily limits can't be negative")
+	}
+	if c.RatePerMinute < 0 || c.LikesPerDay < 0 {
+		return fmt.Errorf("swipe rate limits can't be negative")
+	}
+	if c.MassLikerWindow <= 0 || c.MassLikerInterval <= 0 || c.MassLikerMinSwipes <= 0 {
+		return fmt.Errorf("mass liker window, interval and min swipes must be positive")
+	}
+	if c.MassLikerRatio <= 0 || c.MassLikerRatio > 1 {
+		return fmt.Errorf("mass liker ratio must be in (0, 1]")
+	}
+	return nil
+}
+
+// Validate checks that feed weights are usable
+func (c *FeedConfig) Validate() error {
+	weights := []float64{c.PersonalityWeight, c.InterestsWeight, c.DistanceWeight, c.ActivityWeight, c.EmbeddingWeight}
+	total := 0.0
+	for _, w := range weights {
+		if w < 0 {
+			return fmt.Errorf("feed weights can't be negative")
+		}
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("at least one feed weight must be positive")
+	}
+	if c.ActivityHalfLife <= 0 {
+		return fmt.Errorf("feed activity half-life must be positive")
+	}
+	return nil
+}
+
+// DevAuthEnabled reports whether dev-auth endpoints (login without VK) are mounted,
+// only the development environment has them
+func (c *ServerConfig) DevAuthEnabled() bool {
+	return c.Env == "development"
+}
+
 // GetDSN returns PostgreSQL connection string
 func (c *DatabaseConfig) GetDSN() string {
 	return fmt.Sprintf(
@@ -171,3 +463,14 @@ func (c *DatabaseConfig) GetDSN() string {
 func (c *RedisConfig) GetAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
+
+// splitList splits a comma-separated value skipping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,9 +1,10 @@
 package handler
 
 import (
-	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
 	"github.com/gdugdh24/mpit2026-backend/internal/usecase/auth"
 	"github.com/gin-gonic/gin"
 )
@@ -26,10 +27,35 @@ type VKAuthRequest struct {
 
 // AuthResponse is the response structure
 type AuthResponse struct {
-	Token     string      `json:"token"`
-	ExpiresAt int64       `json:"expires_at"`
-	User      interface{} `json:"user"`
-	IsNewUser bool        `json:"is_new_user"`
+	Token            string      `json:"token"`
+	ExpiresAt        int64       `json:"expires_at"`
+	RefreshToken     string      `json:"refresh_token"`
+	RefreshExpiresAt int64       `json:"refresh_expires_at"`
+	User             interface{} `json:"user,omitempty"`
+	IsNewUser        bool        `json:"is_new_user"`
+}
+
+// newAuthResponse converts use case auth result to the response
+func newAuthResponse(result *auth.AuthResponse) AuthResponse {
+	resp := newTokenResponse(result.TokenPair)
+	resp.User = result.User
+	resp.IsNewUser = result.IsNewUser
+	return resp
+}
+
+// newTokenResponse converts a token pair to the response with unix timestamps
+func newTokenResponse(tokens *auth.TokenPair) AuthResponse {
+	return AuthResponse{
+		Token:            tokens.Token,
+		ExpiresAt:        tokens.ExpiresAt.Unix(),
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt.Unix(),
+	}
+}
+
+// RefreshRequest represents token refresh request
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 // VKAuth handles VK Mini App authentication
@@ -53,9 +79,6 @@ func (h *AuthHandler) VKAuth(c *gin.Context) {
 		return
 	}
 
-	// Debug logging
-	c.Writer.Header().Set("X-Debug-VK-Params", fmt.Sprintf("%+v", req.VKParams))
-
 	deviceInfo := c.GetHeader("User-Agent")
 	ipAddress := c.ClientIP()
 
@@ -68,6 +91,12 @@ func (h *AuthHandler) VKAuth(c *gin.Context) {
 		case "invalid VK signature":
 			statusCode = http.StatusUnauthorized
 			message = "invalid VK signature"
+		case "VK launch params expired":
+			statusCode = http.StatusUnauthorized
+			message = "VK launch params expired"
+		case "VK launch params already used":
+			statusCode = http.StatusUnauthorized
+			message = "VK launch params already used"
 		case "invalid input":
 			statusCode = http.StatusBadRequest
 			message = "invalid VK parameters"
@@ -79,36 +108,23 @@ func (h *AuthHandler) VKAuth(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, AuthResponse{
-		Token:     result.Token,
-		ExpiresAt: result.ExpiresAt.Unix(),
-		User:      result.User,
-		IsNewUser: result.IsNewUser,
-	})
+	c.JSON(http.StatusOK, newAuthResponse(result))
 }
 
-// TestAuthRequest represents test authentication request
-type TestAuthRequest struct {
-	VKID      int    `json:"vk_id" binding:"required"`
-	FirstName string `json:"first_name" binding:"required"`
-	LastName  string `json:"last_name" binding:"required"`
-	Gender    string `json:"gender" binding:"required,oneof=male female"`
-	BirthDate string `json:"birth_date" binding:"required"` // Format: YYYY-MM-DD
-}
-
-// TestAuth creates a test user without VK signature validation (for development/testing only)
-// @Summary Test authentication
-// @Description Create test user without VK validation (dev only)
+// Refresh exchanges a refresh token for a new token pair
+// @Summary Refresh tokens
+// @Description Rotate refresh token and issue a new access token. Reusing a refresh token revokes the session.
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param request body TestAuthRequest true "Test user data"
+// @Param request body RefreshRequest true "Refresh token"
 // @Success 200 {object} AuthResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
-// @Router /auth/test [post]
-func (h *AuthHandler) TestAuth(c *gin.Context) {
-	var req TestAuthRequest
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "invalid request body",
@@ -116,36 +132,31 @@ func (h *AuthHandler) TestAuth(c *gin.Context) {
 		return
 	}
 
-	// Create mock VK params
-	vkParams := map[string]string{
-		"vk_user_id":  fmt.Sprintf("%d", req.VKID),
-		"vk_app_id":   "test",
-		"vk_platform": "test",
-		"vk_ts":       "0",
-		"sign":        "test_bypass",
-		"first_name":  req.FirstName,
-		"last_name":   req.LastName,
-		"gender":      req.Gender,
-		"birth_date":  req.BirthDate,
-	}
+	tokens, err := h.authUseCase.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		statusCode := http.StatusUnauthorized
+		message := "invalid refresh token"
 
-	deviceInfo := "test-client"
-	ipAddress := c.ClientIP()
+		switch err {
+		case domain.ErrSessionNotFound:
+			message = "session not found"
+		case domain.ErrSessionExpired:
+			message = "session expired"
+		case domain.ErrRefreshTokenReused:
+			message = "refresh token reuse detected"
+		case domain.ErrInvalidToken:
+		default:
+			statusCode = http.StatusInternalServerError
+			message = "failed to refresh token"
+		}
 
-	result, err := h.authUseCase.AuthenticateVKTest(c.Request.Context(), vkParams, deviceInfo, ipAddress)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: fmt.Sprintf("test auth failed: %v", err),
+		c.JSON(statusCode, ErrorResponse{
+			Error: message,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, AuthResponse{
-		Token:     result.Token,
-		ExpiresAt: result.ExpiresAt.Unix(),
-		User:      result.User,
-		IsNewUser: result.IsNewUser,
-	})
+	c.JSON(http.StatusOK, newTokenResponse(tokens))
 }
 
 // Logout handles user logout
@@ -184,6 +195,123 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	})
 }
 
+// GetSessions returns active sessions (devices) of the current user
+// @Summary List sessions
+// @Description Get active sessions with device, IP, created and last seen time. The session of the request is marked as current.
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/sessions [get]
+func (h *AuthHandler) GetSessions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	sessions, err := h.authUseCase.GetSessions(c.Request.Context(), userID.(int), c.GetInt("session_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "failed to get sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+		"total":    len(sessions),
+	})
+}
+
+// RevokeSession deletes a session of the current user
+// @Summary Revoke session
+// @Description Log out a device. Revoking the current session works like logout.
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Param session_id path int true "Session ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/sessions/{session_id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	sessionID, err := strconv.Atoi(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "invalid session_id",
+		})
+		return
+	}
+
+	if err := h.authUseCase.RevokeSession(c.Request.Context(), userID.(int), sessionID); err != nil {
+		statusCode := http.StatusInternalServerError
+		message := "failed to revoke session"
+
+		switch err {
+		case domain.ErrSessionNotFound:
+			statusCode = http.StatusNotFound
+			message = "session not found"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error: message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "session revoked successfully",
+	})
+}
+
+// LogoutAll logs out the current user on all devices
+// @Summary Logout everywhere
+// @Description Delete all sessions of the user including the current one
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	count, err := h.authUseCase.LogoutAll(c.Request.Context(), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "logout failed",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "logged out from all devices",
+		"revoked_sessions": count,
+	})
+}
+
 // Me returns current user info
 // @Summary Get current user
 // @Description Get authenticated user information
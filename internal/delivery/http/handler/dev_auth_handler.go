@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// DevAuthHandler issues sessions without VK verification.
+// Mounted only in development mode (see ServerConfig.DevAuthEnabled).
+type DevAuthHandler struct {
+	authUseCase *auth.VKAuthUseCase
+}
+
+func NewDevAuthHandler(authUseCase *auth.VKAuthUseCase) *DevAuthHandler {
+	return &DevAuthHandler{
+		authUseCase: authUseCase,
+	}
+}
+
+// TestAuthRequest represents test authentication request
+type TestAuthRequest struct {
+	VKID      int    `json:"vk_id" binding:"required"`
+	FirstName string `json:"first_name" binding:"required"`
+	LastName  string `json:"last_name" binding:"required"`
+	Gender    string `json:"gender" binding:"required,oneof=male female"`
+	BirthDate string `json:"birth_date" binding:"required"` // Format: YYYY-MM-DD
+}
+
+// TestAuth creates a test user without VK signature validation (for development/testing only)
+// @Summary Test authentication
+// @Description Create test user without VK validation (dev only)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body TestAuthRequest true "Test user data"
+// @Success 200 {object} AuthResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/test [post]
+func (h *DevAuthHandler) TestAuth(c *gin.Context) {
+	var req TestAuthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "invalid request body",
+		})
+		return
+	}
+
+	// Create mock VK params
+	vkParams := map[string]string{
+		"vk_user_id":  fmt.Sprintf("%d", req.VKID),
+		"vk_app_id":   "test",
+		"vk_platform": "test",
+		"vk_ts":       "0",
+		"sign":        "test_bypass",
+		"first_name":  req.FirstName,
+		"last_name":   req.LastName,
+		"gender":      req.Gender,
+		"birth_date":  req.BirthDate,
+	}
+
+	deviceInfo := "test-client"
+	ipAddress := c.ClientIP()
+
+	result, err := h.authUseCase.AuthenticateVKTest(c.Request.Context(), vkParams, deviceInfo, ipAddress)
+	if err != nil {
+		h.respondError(c, err, "test auth failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, newAuthResponse(result))
+}
+
+// DevLoginRequest represents dev login request
+type DevLoginRequest struct {
+	UserID int `json:"user_id" binding:"required,min=1"`
+}
+
+// Login creates a session for an existing seeded user by id
+// @Summary Dev login
+// @Description Create session for existing user by id without VK validation (dev only)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body DevLoginRequest true "User ID"
+// @Success 200 {object} AuthResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/dev/login [post]
+func (h *DevAuthHandler) Login(c *gin.Context) {
+	var req DevLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "invalid request body",
+		})
+		return
+	}
+
+	result, err := h.authUseCase.AuthenticateDev(c.Request.Context(), req.UserID, "dev-client", c.ClientIP())
+	if err != nil {
+		h.respondError(c, err, "dev login failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, newAuthResponse(result))
+}
+
+// respondError maps dev auth errors to HTTP responses without exposing internal details
+func (h *DevAuthHandler) respondError(c *gin.Context, err error, fallback string) {
+	statusCode := http.StatusInternalServerError
+	message := fallback
+
+	switch err {
+	case domain.ErrInvalidInput:
+		statusCode = http.StatusBadRequest
+		message = "invalid vk_id"
+	case domain.ErrUserNotFound:
+		statusCode = http.StatusNotFound
+		message = "user not found"
+	case domain.ErrForbidden:
+		statusCode = http.StatusForbidden
+		message = "dev auth is allowed only for seeded users"
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error: message,
+	})
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/realtime"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// sseHeartbeatPeriod keeps proxies from closing an idle stream
+	sseHeartbeatPeriod = 25 * time.Second
+	// sseRetry is the reconnection delay suggested to the client, in milliseconds
+	sseRetry = 3000
+)
+
+type EventsHandler struct {
+	stream *realtime.Stream
+
+	done chan struct{}
+	once sync.Once
+}
+
+func NewEventsHandler(stream *realtime.Stream) *EventsHandler {
+	return &EventsHandler{
+		stream: stream,
+		done:   make(chan struct{}),
+	}
+}
+
+// Close ends open streams, clients reconnect with Last-Event-ID
+func (h *EventsHandler) Close() {
+	h.once.Do(func() {
+		close(h.done)
+	})
+}
+
+// Stream handles GET /events/stream
+// @Summary Server-Sent Events stream
+// @Description Stream of real-time events for clients without WebSocket support. Resumes from Last-Event-ID.
+// @Tags realtime
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param access_token query string false "JWT token (if Authorization header can't be set)"
+// @Param Last-Event-ID header int false "ID of the last received event"
+// @Param last_event_id query int false "Same as Last-Event-ID header"
+// @Success 200 "text/event-stream"
+// @Failure 401 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /events/stream [get]
+func (h *EventsHandler) Stream(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	// EventSource sends the header on reconnect, the query param is for manual resume
+	lastEventIDStr := c.GetHeader("Last-Event-ID")
+	if lastEventIDStr == "" {
+		lastEventIDStr = c.Query("last_event_id")
+	}
+	var lastEventID int64
+	if lastEventIDStr != "" {
+		id, err := strconv.ParseInt(lastEventIDStr, 10, 64)
+		if err != nil || id < 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "invalid last event id",
+			})
+			return
+		}
+		lastEventID = id
+	}
+
+	ctx := c.Request.Context()
+	sub, err := h.stream.Open(ctx, userID.(int), lastEventID)
+	if err != nil {
+		fmt.Printf("❌ [Realtime] Failed to open event stream of user %d: %v\n", userID, err)
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error: "event stream unavailable",
+		})
+		return
+	}
+	defer sub.Cancel()
+
+	// The stream outlives the server write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetry)
+
+	lastSentID := lastEventID
+	if !sub.Complete {
+		// Some events were evicted from the log or the sequence was reset,
+		// the client has to reload its state
+		if event, err := realtime.NewEvent(realtime.EventResync, nil); err == nil {
+			writeSSEEvent(c, event)
+		}
+		lastSentID = 0
+	}
+	for _, event := range sub.Missed {
+		writeSSEEvent(c, event)
+		lastSentID = event.ID
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatPeriod)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.done:
+			return
+		case event, ok := <-sub.Live:
+			if !ok {
+				return
+			}
+			// Skip events already replayed from the log
+			if event.ID != 0 && event.ID <= lastSentID {
+				continue
+			}
+			writeSSEEvent(c, event)
+			if event.ID != 0 {
+				lastSentID = event.ID
+			}
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeSSEEvent writes the event in text/event-stream format
+func writeSSEEvent(c *gin.Context, event *realtime.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	if event.ID != 0 {
+		fmt.Fprintf(c.Writer, "id: %d\n", event.ID)
+	}
+	fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, data)
+}
@@ -2,11 +2,16 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/usecase/feed"
 	"github.com/gin-gonic/gin"
 )
 
+// maxResetDislikesDays caps older_than_days of dislikes reset
+const maxResetDislikesDays = 3650
+
 type FeedHandler struct {
 	feedUseCase *feed.FeedUseCase
 }
@@ -58,13 +63,53 @@ func (h *FeedHandler) GetNextUser(c *gin.Context) {
 	})
 }
 
+// GetDeck handles GET /feed/deck
+// @Summary Get a batch of feed cards
+// @Description Get the next cards of the ranked feed deck. The deck is ranked once and cached, each call moves the server-side cursor forward.
+// @Tags feed
+// @Security BearerAuth
+// @Produce json
+// @Param size query int false "Number of cards (max 50)" default(10)
+// @Success 200 {object} feed.DeckPage
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /feed/deck [get]
+func (h *FeedHandler) GetDeck(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	size := feed.DefaultDeckPageSize
+	if sizeStr := c.Query("size"); sizeStr != "" {
+		if s, err := strconv.Atoi(sizeStr); err == nil && s > 0 && s <= feed.MaxDeckPageSize {
+			size = s
+		}
+	}
+
+	page, err := h.feedUseCase.GetDeck(c.Request.Context(), userID.(int), size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "failed to get feed deck",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
 // ResetDislikes handles POST /feed/reset-dislikes
-// @Summary Reset all dislikes
-// @Description Delete all dislikes to refresh the feed
+// @Summary Reset dislikes
+// @Description Delete dislikes to refresh the feed. With older_than_days only dislikes made earlier are deleted, so stale passes get a second chance.
 // @Tags feed
 // @Security BearerAuth
 // @Produce json
+// @Param older_than_days query int false "Only reset dislikes older than this many days"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /feed/reset-dislikes [post]
@@ -77,7 +122,19 @@ func (h *FeedHandler) ResetDislikes(c *gin.Context) {
 		return
 	}
 
-	count, err := h.feedUseCase.ResetDislikes(c.Request.Context(), userID.(int))
+	var olderThan time.Duration
+	if daysStr := c.Query("older_than_days"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days < 1 || days > maxResetDislikesDays {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "invalid older_than_days",
+			})
+			return
+		}
+		olderThan = time.Duration(days) * 24 * time.Hour
+	}
+
+	count, err := h.feedUseCase.ResetDislikes(c.Request.Context(), userID.(int), olderThan)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error: "failed to reset dislikes",
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/match"
+	"github.com/gin-gonic/gin"
+)
+
+type MatchHandler struct {
+	matchUseCase *match.MatchUseCase
+}
+
+func NewMatchHandler(matchUseCase *match.MatchUseCase) *MatchHandler {
+	return &MatchHandler{
+		matchUseCase: matchUseCase,
+	}
+}
+
+// GetMatches handles GET /matches
+// @Summary Get matches
+// @Description Get active matches with the other user's profile and last message
+// @Tags matches
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /matches [get]
+func (h *MatchHandler) GetMatches(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	// Parse query params
+	limit := 20
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	matches, total, err := h.matchUseCase.GetMatches(c.Request.Context(), userID.(int), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "failed to get matches",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matches": matches,
+		"total":   total,
+	})
+}
+
+// GetMatch handles GET /matches/:match_id
+// @Summary Get match
+// @Description Get match details including AI explanation and icebreakers
+// @Tags matches
+// @Security BearerAuth
+// @Produce json
+// @Param match_id path int true "Match ID"
+// @Success 200 {object} match.MatchDetailResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /matches/{match_id} [get]
+func (h *MatchHandler) GetMatch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	matchID, err := strconv.Atoi(c.Param("match_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "invalid match_id",
+		})
+		return
+	}
+
+	result, err := h.matchUseCase.GetMatch(c.Request.Context(), userID.(int), matchID)
+	if err != nil {
+		h.respondMatchError(c, err, "failed to get match")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Unmatch handles DELETE /matches/:match_id
+// @Summary Unmatch
+// @Description Deactivate match for both users
+// @Tags matches
+// @Security BearerAuth
+// @Produce json
+// @Param match_id path int true "Match ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /matches/{match_id} [delete]
+func (h *MatchHandler) Unmatch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	matchID, err := strconv.Atoi(c.Param("match_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "invalid match_id",
+		})
+		return
+	}
+
+	if err := h.matchUseCase.Unmatch(c.Request.Context(), userID.(int), matchID); err != nil {
+		h.respondMatchError(c, err, "failed to delete match")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "match deleted successfully",
+	})
+}
+
+// respondMatchError maps match access errors to HTTP responses
+func (h *MatchHandler) respondMatchError(c *gin.Context, err error, fallback string) {
+	statusCode := http.StatusInternalServerError
+	message := fallback
+
+	switch err {
+	case domain.ErrMatchNotFound:
+		statusCode = http.StatusNotFound
+		message = "match not found"
+	case domain.ErrNotMatched:
+		statusCode = http.StatusNotFound
+		message = "users are not matched"
+	case domain.ErrForbidden:
+		statusCode = http.StatusForbidden
+		message = "forbidden"
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error: message,
+	})
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/message"
+	"github.com/gin-gonic/gin"
+)
+
+type MessageHandler struct {
+	messageUseCase *message.MessageUseCase
+}
+
+func NewMessageHandler(messageUseCase *message.MessageUseCase) *MessageHandler {
+	return &MessageHandler{
+		messageUseCase: messageUseCase,
+	}
+}
+
+// SendMessage handles POST /messages/:match_id
+// @Summary Send message
+// @Description Send a message to an active match
+// @Tags messages
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param match_id path int true "Match ID"
+// @Param request body message.SendMessageRequest true "Message"
+// @Success 201 {object} domain.Message
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /messages/{match_id} [post]
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	matchID, err := strconv.Atoi(c.Param("match_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "invalid match_id",
+		})
+		return
+	}
+
+	var req message.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "invalid request body",
+		})
+		return
+	}
+
+	result, err := h.messageUseCase.SendMessage(c.Request.Context(), userID.(int), matchID, &req)
+	if err != nil {
+		h.respondMessageError(c, err, "failed to send message")
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// GetMessages handles GET /messages/:match_id
+// @Summary Get messages
+// @Description Get match messages newest first using cursor pagination
+// @Tags messages
+// @Security BearerAuth
+// @Produce json
+// @Param match_id path int true "Match ID"
+// @Param before query int false "Return messages older than this message ID"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} message.MessagesPage
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /messages/{match_id} [get]
+func (h *MessageHandler) GetMessages(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	matchID, err := strconv.Atoi(c.Param("match_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "invalid match_id",
+		})
+		return
+	}
+
+	// Parse query params
+	limit := 50
+	beforeID := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	if beforeStr := c.Query("before"); beforeStr != "" {
+		b, err := strconv.Atoi(beforeStr)
+		if err != nil || b < 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "invalid before cursor",
+			})
+			return
+		}
+		beforeID = b
+	}
+
+	page, err := h.messageUseCase.GetMessages(c.Request.Context(), userID.(int), matchID, beforeID, limit)
+	if err != nil {
+		h.respondMessageError(c, err, "failed to get messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// MarkRead handles PUT /messages/:match_id/read
+// @Summary Mark conversation read
+// @Description Mark received messages in the match as read up to the given message
+// @Tags messages
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param match_id path int true "Match ID"
+// @Param request body message.MarkReadRequest true "Last read message"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /messages/{match_id}/read [put]
+func (h *MessageHandler) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	matchID, err := strconv.Atoi(c.Param("match_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "invalid match_id",
+		})
+		return
+	}
+
+	var req message.MarkReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "invalid request body",
+		})
+		return
+	}
+
+	count, err := h.messageUseCase.MarkRead(c.Request.Context(), userID.(int), matchID, &req)
+	if err != nil {
+		h.respondMessageError(c, err, "failed to mark messages as read")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "messages marked as read",
+		"count":   count,
+	})
+}
+
+// GetUnreadCount handles GET /messages/unread-count
+// @Summary Get unread messages count
+// @Description Get the number of unread messages across all matches
+// @Tags messages
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /messages/unread-count [get]
+func (h *MessageHandler) GetUnreadCount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	count, err := h.messageUseCase.GetUnreadCount(c.Request.Context(), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "failed to get unread count",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"unread_count": count,
+	})
+}
+
+// respondMessageError maps messaging errors to HTTP responses
+func (h *MessageHandler) respondMessageError(c *gin.Context, err error, fallback string) {
+	statusCode := http.StatusInternalServerError
+	message := fallback
+
+	switch err {
+	case domain.ErrEmptyMessage:
+		statusCode = http.StatusBadRequest
+		message = "message content is empty"
+	case domain.ErrMessageTooLong:
+		statusCode = http.StatusBadRequest
+		message = "message content is too long"
+	case domain.ErrUnauthorizedMessage:
+		statusCode = http.StatusForbidden
+		message = "unauthorized to access this conversation"
+	case domain.ErrNotMatched:
+		statusCode = http.StatusForbidden
+		message = "users are not matched"
+	case domain.ErrMatchNotFound:
+		statusCode = http.StatusNotFound
+		message = "match not found"
+	case domain.ErrMessageNotFound:
+		statusCode = http.StatusNotFound
+		message = "message not found"
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error: message,
+	})
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/notification"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationUseCase *notification.NotificationUseCase
+}
+
+func NewNotificationHandler(notificationUseCase *notification.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+// GetNotifications handles GET /notifications
+// @Summary Get notifications
+// @Description Get notifications of the current user, newest first
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Param unread_only query bool false "Only unread notifications" default(false)
+// @Success 200 {object} notification.NotificationsPage
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	// Parse query params
+	limit := 20
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread_only"))
+
+	page, err := h.notificationUseCase.GetNotifications(c.Request.Context(), userID.(int), unreadOnly, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "failed to get notifications",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// MarkAsRead handles PUT /notifications/:notification_id/read
+// @Summary Mark notification as read
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Param notification_id path int true "Notification ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /notifications/{notification_id}/read [put]
+func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	notificationID, err := strconv.Atoi(c.Param("notification_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "invalid notification_id",
+		})
+		return
+	}
+
+	if err := h.notificationUseCase.MarkAsRead(c.Request.Context(), userID.(int), notificationID); err != nil {
+		h.respondNotificationError(c, err, "failed to mark notification as read")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "notification marked as read",
+	})
+}
+
+// MarkAllAsRead handles PUT /notifications/read-all
+// @Summary Mark all notifications as read
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /notifications/read-all [put]
+func (h *NotificationHandler) MarkAllAsRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	count, err := h.notificationUseCase.MarkAllAsRead(c.Request.Context(), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "failed to mark notifications as read",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "all notifications marked as read",
+		"count":   count,
+	})
+}
+
+// GetUnreadCount handles GET /notifications/unread-count
+// @Summary Get unread notifications count
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	count, err := h.notificationUseCase.GetUnreadCount(c.Request.Context(), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "failed to get unread count",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"unread_count": count,
+	})
+}
+
+// DeleteNotification handles DELETE /notifications/:notification_id
+// @Summary Delete notification
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Param notification_id path int true "Notification ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /notifications/{notification_id} [delete]
+func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	notificationID, err := strconv.Atoi(c.Param("notification_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "invalid notification_id",
+		})
+		return
+	}
+
+	if err := h.notificationUseCase.Delete(c.Request.Context(), userID.(int), notificationID); err != nil {
+		h.respondNotificationError(c, err, "failed to delete notification")
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "notification deleted successfully",
+	})
+}
+
+// respondNotificationError maps notification errors to HTTP responses
+func (h *NotificationHandler) respondNotificationError(c *gin.Context, err error, fallback string) {
+	statusCode := http.StatusInternalServerError
+	message := fallback
+
+	switch err {
+	case domain.ErrNotificationNotFound:
+		statusCode = http.StatusNotFound
+		message = "notification not found"
+	}
+
+	c.JSON(statusCode, ErrorResponse{
+		Error: message,
+	})
+}
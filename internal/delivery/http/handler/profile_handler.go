@@ -94,6 +94,12 @@ func (h *ProfileHandler) UpdateMyProfile(c *gin.Context) {
 			})
 			return
 		}
+		if err == domain.ErrInvalidInterestedIn {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error: "failed to update profile",
 		})
@@ -142,6 +148,12 @@ func (h *ProfileHandler) CompleteOnboarding(c *gin.Context) {
 			})
 			return
 		}
+		if err == domain.ErrInvalidInterestedIn {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error: "failed to create profile",
 		})
@@ -151,6 +163,43 @@ func (h *ProfileHandler) CompleteOnboarding(c *gin.Context) {
 	c.JSON(http.StatusCreated, newProfile)
 }
 
+// GetBioSuggestions handles GET /profile/me/bio-suggestions
+// @Summary Suggest bio
+// @Description Generate bio variants for current user's profile with AI Wingman
+// @Tags profile
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} profile.BioSuggestionsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /profile/me/bio-suggestions [get]
+func (h *ProfileHandler) GetBioSuggestions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	suggestions, err := h.profileUseCase.SuggestBio(c.Request.Context(), userID.(int))
+	if err != nil {
+		if err == domain.ErrProfileNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "profile not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "failed to generate bio suggestions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
 // GetProfileByUserID handles GET /profile/:user_id
 // @Summary Get user profile
 // @Description Get another user's profile by user ID
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/realtime"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/message"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+type RealtimeHandler struct {
+	hub            *realtime.Hub
+	messageUseCase *message.MessageUseCase
+	upgrader       websocket.Upgrader
+}
+
+func NewRealtimeHandler(hub *realtime.Hub, messageUseCase *message.MessageUseCase) *RealtimeHandler {
+	h := &RealtimeHandler{
+		hub:            hub,
+		messageUseCase: messageUseCase,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// VK Mini Apps are served from VK domains, CORS is open anyway
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+
+	// Inbound frames sent by clients
+	hub.Handle(realtime.EventTyping, h.handleTyping)
+
+	return h
+}
+
+// Connect handles GET /ws
+// @Summary Real-time WebSocket
+// @Description Upgrade to WebSocket and receive new messages, read receipts, match events and typing indicators
+// @Tags realtime
+// @Security BearerAuth
+// @Param access_token query string false "JWT token (if Authorization header can't be set)"
+// @Success 101 "Switching Protocols"
+// @Failure 401 {object} ErrorResponse
+// @Router /ws [get]
+func (h *RealtimeHandler) Connect(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrader already wrote the error response
+		fmt.Printf("❌ [Realtime] WebSocket upgrade failed: %v\n", err)
+		return
+	}
+
+	h.hub.Serve(userID.(int), conn)
+}
+
+// handleTyping forwards a typing indicator frame to the other user of the match
+func (h *RealtimeHandler) handleTyping(ctx context.Context, userID int, payload json.RawMessage) error {
+	var typing realtime.TypingPayload
+	if err := json.Unmarshal(payload, &typing); err != nil || typing.MatchID <= 0 {
+		return domain.ErrInvalidInput
+	}
+
+	return h.messageUseCase.SendTyping(ctx, userID, typing.MatchID)
+}
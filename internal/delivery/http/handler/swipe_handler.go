@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"math"
 	"net/http"
 	"strconv"
 
@@ -20,8 +22,9 @@ func NewSwipeHandler(swipeUseCase *swipe.SwipeUseCase) *SwipeHandler {
 }
 
 // CreateSwipe handles POST /swipe
-// @Summary Create a swipe (like/dislike)
-// @Description Swipe on a user and check if it's a match
+// @Summary Create a swipe (like/dislike/super like)
+// @Description Swipe on a user and check if it's a match. Swipes are rate limited per minute, likes and super likes per day.
+// @Description X-RateLimit-Remaining and X-Likes-Remaining headers show what is left, 429 responses have Retry-After.
 // @Tags swipe
 // @Security BearerAuth
 // @Accept json
@@ -31,6 +34,7 @@ func NewSwipeHandler(swipeUseCase *swipe.SwipeUseCase) *SwipeHandler {
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /swipe [post]
 func (h *SwipeHandler) CreateSwipe(c *gin.Context) {
@@ -51,6 +55,18 @@ func (h *SwipeHandler) CreateSwipe(c *gin.Context) {
 	}
 
 	result, err := h.swipeUseCase.CreateSwipe(c.Request.Context(), userID.(int), &req)
+	var limitErr *domain.LimitError
+	if errors.As(err, &limitErr) {
+		setLimitErrorHeaders(c, limitErr)
+		message := "too many swipes"
+		if limitErr.Err == domain.ErrLikeQuotaExceeded {
+			message = "daily like limit reached"
+		}
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error: message,
+		})
+		return
+	}
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		message := "failed to create swipe"
@@ -62,6 +78,81 @@ func (h *SwipeHandler) CreateSwipe(c *gin.Context) {
 		case domain.ErrSwipeAlreadyExists:
 			statusCode = http.StatusConflict
 			message = "swipe already exists"
+		case domain.ErrSuperLikeLimitReached:
+			statusCode = http.StatusTooManyRequests
+			message = "daily super like limit reached"
+		}
+
+		c.JSON(statusCode, ErrorResponse{
+			Error: message,
+		})
+		return
+	}
+
+	if result.Limits != nil {
+		if result.Limits.SwipesRemaining >= 0 {
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Limits.SwipesRemaining))
+		}
+		if result.Limits.LikesRemaining >= 0 {
+			c.Header("X-Likes-Remaining", strconv.Itoa(result.Limits.LikesRemaining))
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// setLimitErrorHeaders tells the client when to retry and that the exhausted limit has nothing left
+func setLimitErrorHeaders(c *gin.Context, err *domain.LimitError) {
+	retryAfter := int(math.Ceil(err.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+
+	switch err.Err {
+	case domain.ErrSwipeRateLimited:
+		c.Header("X-RateLimit-Limit", strconv.Itoa(err.Limit))
+		c.Header("X-RateLimit-Remaining", "0")
+	case domain.ErrLikeQuotaExceeded:
+		c.Header("X-Likes-Limit", strconv.Itoa(err.Limit))
+		c.Header("X-Likes-Remaining", "0")
+	}
+}
+
+// UndoSwipe handles POST /swipe/undo
+// @Summary Undo the last swipe
+// @Description Revert the most recent swipe within the undo window: the user returns to the top of the deck, preferences learned from a like are restored and a match created by it is removed
+// @Tags swipe
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} swipe.UndoResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /swipe/undo [post]
+func (h *SwipeHandler) UndoSwipe(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "unauthorized",
+		})
+		return
+	}
+
+	result, err := h.swipeUseCase.UndoSwipe(c.Request.Context(), userID.(int))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		message := "failed to undo swipe"
+
+		switch err {
+		case domain.ErrSwipeNotFound:
+			statusCode = http.StatusNotFound
+			message = "no swipe to undo"
+		case domain.ErrUndoWindowExpired:
+			statusCode = http.StatusConflict
+			message = "swipe is too old to undo"
+		case domain.ErrUndoLimitReached:
+			statusCode = http.StatusTooManyRequests
+			message = "daily undo limit reached"
 		}
 
 		c.JSON(statusCode, ErrorResponse{
@@ -40,34 +40,71 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			return
 		}
 
-		token := parts[1]
+		m.authenticate(c, parts[1])
+	}
+}
 
-		// Verify token
-		userID, err := m.authUseCase.VerifyToken(c.Request.Context(), token)
-		if err != nil {
-			statusCode := http.StatusUnauthorized
-			message := "invalid token"
-
-			switch err.Error() {
-			case "session not found":
-				message = "session not found"
-			case "session expired":
-				message = "session expired"
+// RequireStreamAuth validates JWT token for streaming endpoints (WebSocket, SSE).
+// Browsers can't set headers on these connections, so the token may also be
+// passed in the access_token query parameter.
+func (m *AuthMiddleware) RequireStreamAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("access_token")
+
+		if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": "invalid authorization header format",
+				})
+				c.Abort()
+				return
 			}
+			token = parts[1]
+		}
 
-			c.JSON(statusCode, gin.H{
-				"error": message,
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "missing authorization token",
 			})
 			c.Abort()
 			return
 		}
 
-		// Set user_id in context for handlers
-		c.Set("user_id", userID)
-		c.Set("token", token)
+		m.authenticate(c, token)
+	}
+}
 
-		c.Next()
+// authenticate verifies the token and sets user_id and session_id in context
+func (m *AuthMiddleware) authenticate(c *gin.Context, token string) {
+	// Verify token
+	session, err := m.authUseCase.VerifySession(c.Request.Context(), token)
+	if err != nil {
+		statusCode := http.StatusUnauthorized
+		message := "invalid token"
+
+		switch err.Error() {
+		case "session not found":
+			message = "session not found"
+		case "session expired":
+			message = "session expired"
+		case "token expired":
+			message = "token expired"
+		}
+
+		c.JSON(statusCode, gin.H{
+			"error": message,
+		})
+		c.Abort()
+		return
 	}
+
+	// Set user_id in context for handlers
+	c.Set("user_id", session.UserID)
+	c.Set("session_id", session.ID)
+	c.Set("token", token)
+
+	c.Next()
 }
 
 // OptionalAuth is a middleware that validates token if present but doesn't require it
@@ -86,9 +123,10 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		}
 
 		token := parts[1]
-		userID, err := m.authUseCase.VerifyToken(c.Request.Context(), token)
+		session, err := m.authUseCase.VerifySession(c.Request.Context(), token)
 		if err == nil {
-			c.Set("user_id", userID)
+			c.Set("user_id", session.UserID)
+			c.Set("session_id", session.ID)
 			c.Set("token", token)
 		}
 
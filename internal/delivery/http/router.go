@@ -1,35 +1,55 @@
 package http
 
 import (
+	"expvar"
+
 	"github.com/gdugdh24/mpit2026-backend/internal/delivery/http/handler"
 	"github.com/gdugdh24/mpit2026-backend/internal/delivery/http/middleware"
 	"github.com/gin-gonic/gin"
 )
 
 type Router struct {
-	authHandler    *handler.AuthHandler
-	profileHandler *handler.ProfileHandler
-	bigFiveHandler *handler.BigFiveHandler
-	feedHandler    *handler.FeedHandler
-	swipeHandler   *handler.SwipeHandler
-	authMiddleware *middleware.AuthMiddleware
+	authHandler         *handler.AuthHandler
+	devAuthHandler      *handler.DevAuthHandler
+	profileHandler      *handler.ProfileHandler
+	bigFiveHandler      *handler.BigFiveHandler
+	feedHandler         *handler.FeedHandler
+	swipeHandler        *handler.SwipeHandler
+	matchHandler        *handler.MatchHandler
+	messageHandler      *handler.MessageHandler
+	notificationHandler *handler.NotificationHandler
+	realtimeHandler     *handler.RealtimeHandler
+	eventsHandler       *handler.EventsHandler
+	authMiddleware      *middleware.AuthMiddleware
 }
 
 func NewRouter(
 	authHandler *handler.AuthHandler,
+	devAuthHandler *handler.DevAuthHandler,
 	profileHandler *handler.ProfileHandler,
 	bigFiveHandler *handler.BigFiveHandler,
 	feedHandler *handler.FeedHandler,
 	swipeHandler *handler.SwipeHandler,
+	matchHandler *handler.MatchHandler,
+	messageHandler *handler.MessageHandler,
+	notificationHandler *handler.NotificationHandler,
+	realtimeHandler *handler.RealtimeHandler,
+	eventsHandler *handler.EventsHandler,
 	authMiddleware *middleware.AuthMiddleware,
 ) *Router {
 	return &Router{
-		authHandler:    authHandler,
-		profileHandler: profileHandler,
-		bigFiveHandler: bigFiveHandler,
-		feedHandler:    feedHandler,
-		swipeHandler:   swipeHandler,
-		authMiddleware: authMiddleware,
+		authHandler:         authHandler,
+		devAuthHandler:      devAuthHandler,
+		profileHandler:      profileHandler,
+		bigFiveHandler:      bigFiveHandler,
+		feedHandler:         feedHandler,
+		swipeHandler:        swipeHandler,
+		matchHandler:        matchHandler,
+		messageHandler:      messageHandler,
+		notificationHandler: notificationHandler,
+		realtimeHandler:     realtimeHandler,
+		eventsHandler:       eventsHandler,
+		authMiddleware:      authMiddleware,
 	}
 }
 
@@ -42,12 +62,23 @@ func (r *Router) Setup() *gin.Engine {
 	// Health check (supports both GET and HEAD)
 	healthHandler := func(c *gin.Context) {
 		c.JSON(200, gin.H{
-			"status": "ok",
+			"status":   "ok",
+			"dev_auth": r.devAuthHandler != nil,
 		})
 	}
 	router.GET("/health", healthHandler)
 	router.HEAD("/health", healthHandler)
 
+	// Runtime counters (AI Wingman calls, cache hits, fallbacks and errors in "ai_wingman").
+	// Only this map is public: the default expvar handler also exposes the command line and memory stats.
+	router.GET("/debug/vars", func(c *gin.Context) {
+		vars := "{}"
+		if v := expvar.Get("ai_wingman"); v != nil {
+			vars = v.String()
+		}
+		c.Data(200, "application/json; charset=utf-8", []byte(`{"ai_wingman": `+vars+`}`))
+	})
+
 	// API v1
 	v1 := router.Group("/api/v1")
 	{
@@ -55,9 +86,18 @@ func (r *Router) Setup() *gin.Engine {
 		auth := v1.Group("/auth")
 		{
 			auth.POST("/vk", r.authHandler.VKAuth)
-			auth.POST("/test", r.authHandler.TestAuth) // Test endpoint for development
+			auth.POST("/refresh", r.authHandler.Refresh)
 			auth.POST("/logout", r.authMiddleware.RequireAuth(), r.authHandler.Logout)
 			auth.GET("/me", r.authMiddleware.RequireAuth(), r.authHandler.Me)
+			auth.POST("/logout-all", r.authMiddleware.RequireAuth(), r.authHandler.LogoutAll)
+			auth.GET("/sessions", r.authMiddleware.RequireAuth(), r.authHandler.GetSessions)
+			auth.DELETE("/sessions/:session_id", r.authMiddleware.RequireAuth(), r.authHandler.RevokeSession)
+
+			// Dev auth (development mode only, nil otherwise)
+			if r.devAuthHandler != nil {
+				auth.POST("/test", r.devAuthHandler.TestAuth)
+				auth.POST("/dev/login", r.devAuthHandler.Login)
+			}
 		}
 
 		// Protected routes
@@ -69,6 +109,7 @@ func (r *Router) Setup() *gin.Engine {
 			{
 				profile.GET("/me", r.profileHandler.GetMyProfile)
 				profile.PUT("/me", r.profileHandler.UpdateMyProfile)
+				profile.GET("/me/bio-suggestions", r.profileHandler.GetBioSuggestions)
 				profile.POST("/complete-onboarding", r.profileHandler.CompleteOnboarding)
 				profile.GET("/:user_id", r.profileHandler.GetProfileByUserID)
 			}
@@ -85,6 +126,7 @@ func (r *Router) Setup() *gin.Engine {
 			feed := protected.Group("/feed")
 			{
 				feed.GET("/next", r.feedHandler.GetNextUser)
+				feed.GET("/deck", r.feedHandler.GetDeck)
 				feed.POST("/reset-dislikes", r.feedHandler.ResetDislikes)
 			}
 
@@ -92,15 +134,46 @@ func (r *Router) Setup() *gin.Engine {
 			swipe := protected.Group("/swipe")
 			{
 				swipe.POST("", r.swipeHandler.CreateSwipe)
+				swipe.POST("/undo", r.swipeHandler.UndoSwipe)
 				swipe.GET("/likes-received", r.swipeHandler.GetLikesReceived)
 			}
 
-			// TODO: Add match routes
-			// TODO: Add message routes
-			// TODO: Add notification routes
+			// Match routes
+			matches := protected.Group("/matches")
+			{
+				matches.GET("", r.matchHandler.GetMatches)
+				matches.GET("/:match_id", r.matchHandler.GetMatch)
+				matches.DELETE("/:match_id", r.matchHandler.Unmatch)
+			}
+
+			// Message routes
+			messages := protected.Group("/messages")
+			{
+				messages.GET("/unread-count", r.messageHandler.GetUnreadCount)
+				messages.GET("/:match_id", r.messageHandler.GetMessages)
+				messages.POST("/:match_id", r.messageHandler.SendMessage)
+				messages.PUT("/:match_id/read", r.messageHandler.MarkRead)
+			}
+
+			// Notification routes
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", r.notificationHandler.GetNotifications)
+				notifications.GET("/unread-count", r.notificationHandler.GetUnreadCount)
+				notifications.PUT("/read-all", r.notificationHandler.MarkAllAsRead)
+				notifications.PUT("/:notification_id/read", r.notificationHandler.MarkAsRead)
+				notifications.DELETE("/:notification_id", r.notificationHandler.DeleteNotification)
+			}
+
 			// TODO: Add dashboard /me route
 		}
 
+		// Real-time WebSocket (token may be passed as query param)
+		v1.GET("/ws", r.authMiddleware.RequireStreamAuth(), r.realtimeHandler.Connect)
+
+		// Server-Sent Events fallback for clients without WebSocket
+		v1.GET("/events/stream", r.authMiddleware.RequireStreamAuth(), r.eventsHandler.Stream)
+
 		// Big Five questions (public)
 		v1.GET("/big-five/questions", r.bigFiveHandler.GetQuestions)
 	}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 type BigFiveResult struct {
 	ID                int       `json:"id" db:"id"`
@@ -14,3 +17,42 @@ type BigFiveResult struct {
 	CreatedAt         time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// Traits is a Big Five personality vector, every trait is in the 0..1 range
+type Traits struct {
+	Openness          float64 `json:"openness"`
+	Conscientiousness float64 `json:"conscientiousness"`
+	Extraversion      float64 `json:"extraversion"`
+	Agreeableness     float64 `json:"agreeableness"`
+	Neuroticism       float64 `json:"neuroticism"`
+}
+
+// MaxTraitsDistance is the distance between two opposite trait vectors, sqrt(5)
+var MaxTraitsDistance = math.Sqrt(5)
+
+// Traits returns the measured personality of the user
+func (r *BigFiveResult) Traits() *Traits {
+	return &Traits{
+		Openness:          r.Openness,
+		Conscientiousness: r.Conscientiousness,
+		Extraversion:      r.Extraversion,
+		Agreeableness:     r.Agreeableness,
+		Neuroticism:       r.Neuroticism,
+	}
+}
+
+// Distance returns the euclidean distance between two trait vectors
+func (t *Traits) Distance(other *Traits) float64 {
+	d := 0.0
+	d += math.Pow(t.Openness-other.Openness, 2)
+	d += math.Pow(t.Conscientiousness-other.Conscientiousness, 2)
+	d += math.Pow(t.Extraversion-other.Extraversion, 2)
+	d += math.Pow(t.Agreeableness-other.Agreeableness, 2)
+	d += math.Pow(t.Neuroticism-other.Neuroticism, 2)
+	return math.Sqrt(d)
+}
+
+// Similarity returns 1 for equal vectors and 0 for opposite ones
+func (t *Traits) Similarity(other *Traits) float64 {
+	return 1 - t.Distance(other)/MaxTraitsDistance
+}
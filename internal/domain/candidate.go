@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// CandidateFilter describes who can be shown in the feed of a user
+type CandidateFilter struct {
+	// UserID is the feed owner, excluded together with everyone they already swiped
+	UserID int
+	// CandidateID limits the result to a single user, 0 means any
+	CandidateID int
+	// Genders limits candidates to these genders, empty means any
+	Genders []Gender
+	// SeekerGender is the feed owner's gender, candidates must be interested in it
+	SeekerGender Gender
+	City         *string
+	MinAge       *int
+	MaxAge       *int
+	// Lat and Lon are coordinates of the feed owner, distance is computed only if both are set
+	Lat           *float64
+	Lon           *float64
+	MaxDistanceKm *int
+}
+
+// Candidate is a feed candidate with the user fields needed for scoring
+type Candidate struct {
+	Profile      *Profile
+	Gender       Gender
+	BirthDate    time.Time
+	LastOnlineAt *time.Time
+	// Traits is the measured personality, nil if the candidate didn't take the Big Five test
+	Traits *Traits
+	// Embedding is the combined interest embedding, nil if not computed
+	Embedding []float32
+	// DistanceKm is nil if either side has no location
+	DistanceKm *float64
+	// SuperLikedMe is true if the candidate super liked the feed owner
+	SuperLikedMe bool
+	// MassLiker is true if the candidate is flagged for liking almost everyone
+	MassLiker bool
+}
+
+func (c *Candidate) Age() int {
+	return int(time.Since(c.BirthDate).Hours() / 24 / 365.25)
+}
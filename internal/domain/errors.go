@@ -4,37 +4,59 @@ import "errors"
 
 var (
 	// User errors
-	ErrUserNotFound         = errors.New("user not found")
-	ErrUserAlreadyExists    = errors.New("user already exists")
-	ErrInvalidCredentials   = errors.New("invalid credentials")
-	ErrInvalidVKSignature   = errors.New("invalid VK signature")
-	ErrVKTokenExpired       = errors.New("VK token expired")
+	ErrUserNotFound           = errors.New("user not found")
+	ErrUserAlreadyExists      = errors.New("user already exists")
+	ErrInvalidCredentials     = errors.New("invalid credentials")
+	ErrInvalidVKSignature     = errors.New("invalid VK signature")
+	ErrVKTokenExpired         = errors.New("VK token expired")
+	ErrVKLaunchParamsExpired  = errors.New("VK launch params expired")
+	ErrVKLaunchParamsReplayed = errors.New("VK launch params already used")
 
 	// Profile errors
 	ErrProfileNotFound      = errors.New("profile not found")
 	ErrProfileAlreadyExists = errors.New("profile already exists")
+	ErrInvalidInterestedIn  = errors.New("interested_in must contain male and/or female")
 
 	// Session errors
-	ErrSessionNotFound      = errors.New("session not found")
-	ErrSessionExpired       = errors.New("session expired")
-	ErrInvalidToken         = errors.New("invalid token")
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrSessionExpired     = errors.New("session expired")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrTokenExpired       = errors.New("token expired")
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
 
 	// Swipe errors
-	ErrSwipeAlreadyExists   = errors.New("swipe already exists")
-	ErrCannotSwipeSelf      = errors.New("cannot swipe yourself")
+	ErrSwipeAlreadyExists    = errors.New("swipe already exists")
+	ErrCannotSwipeSelf       = errors.New("cannot swipe yourself")
+	ErrSwipeNotFound         = errors.New("swipe not found")
+	ErrUndoWindowExpired     = errors.New("swipe is too old to undo")
+	ErrUndoLimitReached      = errors.New("daily undo limit reached")
+	ErrSuperLikeLimitReached = errors.New("daily super like limit reached")
+	ErrSwipeRateLimited      = errors.New("too many swipes")
+	ErrLikeQuotaExceeded     = errors.New("daily like limit reached")
 
 	// Match errors
-	ErrMatchNotFound        = errors.New("match not found")
-	ErrNotMatched           = errors.New("users are not matched")
-	ErrMatchAlreadyExists   = errors.New("match already exists")
+	ErrMatchNotFound      = errors.New("match not found")
+	ErrNotMatched         = errors.New("users are not matched")
+	ErrMatchAlreadyExists = errors.New("match already exists")
 
 	// Message errors
-	ErrMessageNotFound      = errors.New("message not found")
-	ErrUnauthorizedMessage  = errors.New("unauthorized to access message")
+	ErrMessageNotFound     = errors.New("message not found")
+	ErrUnauthorizedMessage = errors.New("unauthorized to access message")
+	ErrEmptyMessage        = errors.New("message content is empty")
+	ErrMessageTooLong      = errors.New("message content is too long")
+
+	// Notification errors
+	ErrNotificationNotFound = errors.New("notification not found")
+
+	// Big Five errors
+	ErrBigFiveNotFound = errors.New("big five result not found")
+
+	// Job errors
+	ErrJobNotFound = errors.New("job not found")
 
 	// General errors
-	ErrInvalidInput         = errors.New("invalid input")
-	ErrUnauthorized         = errors.New("unauthorized")
-	ErrForbidden            = errors.New("forbidden")
-	ErrInternalServer       = errors.New("internal server error")
+	ErrInvalidInput   = errors.New("invalid input")
+	ErrUnauthorized   = errors.New("unauthorized")
+	ErrForbidden      = errors.New("forbidden")
+	ErrInternalServer = errors.New("internal server error")
 )
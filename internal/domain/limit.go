@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// LimitError is returned when a user runs out of a rate limit or quota.
+// Err is the sentinel error describing the limit.
+type LimitError struct {
+	Err   error
+	Limit int
+	// RetryAfter is the time until the limit resets
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
@@ -1,15 +1,36 @@
 package domain
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// MatchDeactivation is the reason a match was deactivated
+type MatchDeactivation string
+
+const (
+	// MatchDeactivatedByUndo means the like that created the match was undone,
+	// a new mutual like restores the match
+	MatchDeactivatedByUndo MatchDeactivation = "undo"
+	// MatchDeactivatedByUnmatch means a user removed the match, it is never restored
+	MatchDeactivatedByUnmatch MatchDeactivation = "unmatch"
+)
 
 type Match struct {
-	ID          int       `json:"id" db:"id"`
-	User1ID     int       `json:"user1_id" db:"user1_id"`
-	User2ID     int       `json:"user2_id" db:"user2_id"`
-	IsActive    bool      `json:"is_active" db:"is_active"`
-	Explanation *string   `json:"explanation" db:"match_explanation"`
-	Icebreakers []string  `json:"icebreakers" db:"icebreakers"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ID          int     `json:"id" db:"id"`
+	User1ID     int     `json:"user1_id" db:"user1_id"`
+	User2ID     int     `json:"user2_id" db:"user2_id"`
+	IsActive    bool    `json:"is_active" db:"is_active"`
+	Explanation *string `json:"explanation" db:"match_explanation"`
+	// Icebreakers are shown to each user separately, see IcebreakersFor
+	Icebreakers *Icebreakers `json:"-" db:"icebreakers"`
+	// PromptVersion is the prompt version the AI content was generated with
+	PromptVersion *string `json:"-" db:"ai_prompt_version"`
+	// DeactivationReason is set for inactive matches
+	DeactivationReason *MatchDeactivation `json:"-" db:"deactivation_reason"`
+	CreatedAt          time.Time          `json:"created_at" db:"created_at"`
 }
 
 func (m *Match) HasUser(userID int) bool {
@@ -25,3 +46,43 @@ func (m *Match) GetOtherUserID(userID int) (int, bool) {
 	}
 	return 0, false
 }
+
+// IcebreakersFor returns the opening lines the user can send to the other user of the match
+func (m *Match) IcebreakersFor(userID int) []string {
+	if m.Icebreakers == nil {
+		return nil
+	}
+	switch userID {
+	case m.User1ID:
+		return m.Icebreakers.User1
+	case m.User2ID:
+		return m.Icebreakers.User2
+	}
+	return nil
+}
+
+// Icebreakers are AI generated opening lines for each side of a match stored as JSONB.
+// User1 are lines for user1 to send to user2 and User2 the other way round.
+type Icebreakers struct {
+	User1 []string `json:"user1"`
+	User2 []string `json:"user2"`
+}
+
+func (i Icebreakers) Value() (driver.Value, error) {
+	data, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (i *Icebreakers) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, i)
+	case string:
+		return json.Unmarshal([]byte(v), i)
+	default:
+		return fmt.Errorf("cannot scan %T into Icebreakers", src)
+	}
+}
@@ -1,11 +1,94 @@
 package domain
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// NotificationKind is the type of a notification
+type NotificationKind string
+
+const (
+	NotificationKindNewMatch     NotificationKind = "new_match"
+	NotificationKindLikeReceived NotificationKind = "like_received"
+	NotificationKindSuperLike    NotificationKind = "super_like"
+	NotificationKindNewMessage   NotificationKind = "new_message"
+	NotificationKindSystem       NotificationKind = "system"
+)
 
 type Notification struct {
-	ID        int       `json:"id" db:"id"`
-	UserID    int       `json:"user_id" db:"user_id"`
-	Content   string    `json:"content" db:"content"`
-	IsRead    bool      `json:"is_read" db:"is_read"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID        int                 `json:"id" db:"id"`
+	UserID    int                 `json:"user_id" db:"user_id"`
+	Kind      NotificationKind    `json:"kind" db:"kind"`
+	Content   string              `json:"content" db:"content"`
+	Payload   NotificationPayload `json:"payload" db:"payload"`
+	IsRead    bool                `json:"is_read" db:"is_read"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+}
+
+// NotificationPayload is structured notification data stored as JSONB
+type NotificationPayload json.RawMessage
+
+// NewNotificationPayload encodes the value as notification payload
+func NewNotificationPayload(v interface{}) (NotificationPayload, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return NotificationPayload(data), nil
+}
+
+func (p NotificationPayload) MarshalJSON() ([]byte, error) {
+	if len(p) == 0 {
+		return []byte("{}"), nil
+	}
+	return p, nil
+}
+
+func (p *NotificationPayload) UnmarshalJSON(data []byte) error {
+	*p = append((*p)[:0], data...)
+	return nil
+}
+
+func (p NotificationPayload) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return "{}", nil
+	}
+	return string(p), nil
+}
+
+func (p *NotificationPayload) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*p = nil
+	case []byte:
+		// Driver reuses the buffer, copy it
+		*p = append(NotificationPayload(nil), v...)
+	case string:
+		*p = NotificationPayload(v)
+	default:
+		return fmt.Errorf("cannot scan %T into NotificationPayload", src)
+	}
+	return nil
+}
+
+// NewMatchNotificationPayload is the payload of a new_match notification
+type NewMatchNotificationPayload struct {
+	MatchID     int `json:"match_id"`
+	OtherUserID int `json:"other_user_id"`
+}
+
+// LikeReceivedNotificationPayload is the payload of like_received and super_like notifications
+type LikeReceivedNotificationPayload struct {
+	SwipeID    int `json:"swipe_id"`
+	FromUserID int `json:"from_user_id"`
+}
+
+// NewMessageNotificationPayload is the payload of a new_message notification
+type NewMessageNotificationPayload struct {
+	MatchID   int `json:"match_id"`
+	MessageID int `json:"message_id"`
+	SenderID  int `json:"sender_id"`
 }
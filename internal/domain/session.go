@@ -3,15 +3,23 @@ package domain
 import "time"
 
 type Session struct {
-	ID         int       `json:"id" db:"id"`
-	UserID     int       `json:"user_id" db:"user_id"`
-	Token      string    `json:"token" db:"token"`
-	DeviceInfo *string   `json:"device_info" db:"device_info"`
-	IPAddress  *string   `json:"ip_address" db:"ip_address"`
-	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	ID               int        `json:"id" db:"id"`
+	UserID           int        `json:"user_id" db:"user_id"`
+	Token            string     `json:"-" db:"token"`
+	RefreshTokenHash *string    `json:"-" db:"refresh_token_hash"`
+	DeviceInfo       *string    `json:"device_info" db:"device_info"`
+	IPAddress        *string    `json:"ip_address" db:"ip_address"`
+	ExpiresAt        time.Time  `json:"expires_at" db:"expires_at"`
+	RefreshedAt      *time.Time `json:"refreshed_at" db:"refreshed_at"`
+	LastSeenAt       *time.Time `json:"last_seen_at" db:"last_seen_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
 }
 
 func (s *Session) IsExpired() bool {
 	return time.Now().After(s.ExpiresAt)
 }
+
+// SeenSince reports whether the session was used after the given time
+func (s *Session) SeenSince(t time.Time) bool {
+	return s.LastSeenAt != nil && s.LastSeenAt.After(t)
+}
@@ -1,11 +1,68 @@
 package domain
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SwipeKind is the type of a swipe
+type SwipeKind string
+
+const (
+	SwipeKindLike      SwipeKind = "like"
+	SwipeKindDislike   SwipeKind = "dislike"
+	SwipeKindSuperLike SwipeKind = "super_like"
+)
+
+// IsLike reports whether the swipe kind counts as a like for matching
+func (k SwipeKind) IsLike() bool {
+	return k == SwipeKindLike || k == SwipeKindSuperLike
+}
 
 type Swipe struct {
+	ID       int       `json:"id" db:"id"`
+	SwiperID int       `json:"swiper_id" db:"swiper_id"`
+	SwipedID int       `json:"swiped_id" db:"swiped_id"`
+	Kind     SwipeKind `json:"kind" db:"kind"`
+	// IsLike is derived from Kind by the database (true for likes and super likes)
+	IsLike    bool      `json:"is_like" db:"is_like"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// IdealBefore is the swiper's "ideal partner" personality before it was learned from this like,
+	// nil if the swipe didn't change it. Used to revert the learning on undo.
+	IdealBefore *IdealSnapshot `json:"-" db:"ideal_before"`
+}
+
+// IdealSnapshot is a saved "ideal partner" personality stored as JSONB, Traits is nil if it wasn't set
+type IdealSnapshot struct {
+	Traits *Traits `json:"traits"`
+}
+
+func (s IdealSnapshot) Value() (driver.Value, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (s *IdealSnapshot) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	default:
+		return fmt.Errorf("cannot scan %T into IdealSnapshot", src)
+	}
+}
+
+// SwipeRewind is a record of an undone swipe, used to limit rewinds per day
+type SwipeRewind struct {
 	ID        int       `json:"id" db:"id"`
-	SwiperID  int       `json:"swiper_id" db:"swiper_id"`
+	UserID    int       `json:"user_id" db:"user_id"`
 	SwipedID  int       `json:"swiped_id" db:"swiped_id"`
-	IsLike    bool      `json:"is_like" db:"is_like"`
+	WasLike   bool      `json:"was_like" db:"was_like"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
@@ -9,18 +9,42 @@ const (
 	GenderFemale Gender = "female"
 )
 
+// IsValid reports whether the gender is one of the supported values
+func (g Gender) IsValid() bool {
+	return g == GenderMale || g == GenderFemale
+}
+
+// Opposite returns the other gender
+func (g Gender) Opposite() Gender {
+	if g == GenderMale {
+		return GenderFemale
+	}
+	return GenderMale
+}
+
+// SeededVKIDMin is the first VK ID reserved for seeded and test users (see scripts/seed.sql)
+const SeededVKIDMin = 900000001
+
 type User struct {
-	ID                int        `json:"id" db:"id"`
-	VKID              int        `json:"vk_id" db:"vk_id"`
-	VKAccessToken     *string    `json:"-" db:"vk_access_token"`
-	VKTokenExpiresAt  *time.Time `json:"-" db:"vk_token_expires_at"`
-	Gender            Gender     `json:"gender" db:"gender"`
-	BirthDate         time.Time  `json:"birth_date" db:"birth_date"`
-	IsVerified        bool       `json:"is_verified" db:"is_verified"`
-	IsOnline          bool       `json:"is_online" db:"is_online"`
-	LastOnlineAt      *time.Time `json:"last_online_at" db:"last_online_at"`
-	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+	ID                   int        `json:"id" db:"id"`
+	VKID                 int        `json:"vk_id" db:"vk_id"`
+	VKAccessToken        *string    `json:"-" db:"vk_access_token"`
+	VKTokenExpiresAt     *time.Time `json:"-" db:"vk_token_expires_at"`
+	Gender               Gender     `json:"gender" db:"gender"`
+	BirthDate            time.Time  `json:"birth_date" db:"birth_date"`
+	IsVerified           bool       `json:"is_verified" db:"is_verified"`
+	IsOnline             bool       `json:"is_online" db:"is_online"`
+	LastOnlineAt         *time.Time `json:"last_online_at" db:"last_online_at"`
+	NotificationsEnabled bool       `json:"notifications_enabled" db:"notifications_enabled"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+	// MassLikerFlaggedAt is set while the user likes almost everyone, nil otherwise
+	MassLikerFlaggedAt *time.Time `json:"-" db:"mass_liker_flagged_at"`
+}
+
+// IsSeeded reports whether the user is a seeded or test account rather than a real VK user
+func (u *User) IsSeeded() bool {
+	return u.VKID >= SeededVKIDMin
 }
 
 func (u *User) Age() int {
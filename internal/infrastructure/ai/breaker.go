@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// breaker is a circuit breaker: it opens after threshold consecutive failures,
+// rejects calls for cooldown and then lets a single probe call through.
+// The state is kept per process.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// newBreaker creates a circuit breaker, a non-positive threshold disables it
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// allow reports whether a call may be made now
+func (b *breaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.probing {
+		return false
+	}
+	// Half-open: one call checks whether the model is back
+	b.probing = true
+	return true
+}
+
+// release gives back the probe taken by allow when the call was not made
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// success closes the circuit
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+}
+
+// failure counts a failed call and opens the circuit once the threshold is reached
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.threshold > 0 && b.failures >= b.threshold {
+		if b.failures == b.threshold {
+			fmt.Printf("⚠️  [AI Wingman] %d consecutive failures, using fallback texts for %v\n", b.failures, b.cooldown)
+		}
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/cache"
+)
+
+func TestBreaker(t *testing.T) {
+	b := newBreaker(2, 10*time.Millisecond)
+
+	b.failure()
+	if !b.allow() {
+		t.Fatal("call rejected below the threshold")
+	}
+	b.failure()
+	if b.allow() {
+		t.Fatal("call allowed by an open circuit")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("probe rejected after the cooldown")
+	}
+	if b.allow() {
+		t.Fatal("second probe allowed while the first one runs")
+	}
+
+	// An unused probe is given back
+	b.release()
+	if !b.allow() {
+		t.Fatal("probe rejected after the previous one was released")
+	}
+
+	// A failed probe opens the circuit for another cooldown
+	b.failure()
+	if b.allow() {
+		t.Fatal("call allowed after a failed probe")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("probe rejected after the cooldown")
+	}
+	b.success()
+	if !b.allow() || !b.allow() {
+		t.Fatal("calls rejected by a closed circuit")
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b := newBreaker(0, time.Minute)
+	for i := 0; i < 5; i++ {
+		b.failure()
+	}
+	if !b.allow() {
+		t.Fatal("disabled breaker rejected a call")
+	}
+}
+
+// failingWingman counts calls of BioSuggestions and fails them while err is set
+type failingWingman struct {
+	FakeWingman
+	calls int
+	err   error
+}
+
+func (w *failingWingman) BioSuggestions(ctx context.Context, version string, user *Person) ([]string, error) {
+	w.calls++
+	if w.err != nil {
+		return nil, w.err
+	}
+	return w.FakeWingman.BioSuggestions(ctx, version, user)
+}
+
+func TestGuardedWingmanReleasesProbeOnSpentBudget(t *testing.T) {
+	ctx := context.Background()
+	alice, bob := &Person{UserID: 1, Name: "Аня"}, &Person{UserID: 2, Name: "Олег"}
+
+	primary := &failingWingman{err: errors.New("model is down")}
+	w := NewGuardedWingman(primary, NewFakeWingman(), cache.NewMemoryStore(), GuardConfig{
+		UserDailyBudget:  1,
+		BreakerThreshold: 1,
+		BreakerCooldown:  10 * time.Millisecond,
+	})
+
+	// The failure opens the circuit and spends alice's budget
+	if _, err := w.BioSuggestions(ctx, FakePromptVersion, alice); err == nil {
+		t.Fatal("BioSuggestions() error = nil, want the primary error")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	primary.err = nil
+
+	// Alice takes the probe but her budget is spent, she gets fallback texts
+	if _, err := w.BioSuggestions(ctx, FakePromptVersion, alice); err != nil {
+		t.Fatalf("BioSuggestions() error = %v", err)
+	}
+	if primary.calls != 1 {
+		t.Fatalf("primary called %d times, want 1", primary.calls)
+	}
+
+	// The probe was not used, bob's call checks whether the model is back
+	if _, err := w.BioSuggestions(ctx, FakePromptVersion, bob); err != nil {
+		t.Fatalf("BioSuggestions() error = %v", err)
+	}
+	if primary.calls != 2 {
+		t.Fatalf("primary called %d times, want 2: the half-open breaker is stuck", primary.calls)
+	}
+}
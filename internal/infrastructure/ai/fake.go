@@ -0,0 +1,124 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// FakePromptVersion is the prompt version of texts built by FakeWingman
+const FakePromptVersion = "fake"
+
+// FakeWingman builds texts from templates without a language model.
+// The same input always gives the same output, it's used in tests and when no model is configured.
+type FakeWingman struct{}
+
+// NewFakeWingman creates a template based AI Wingman
+func NewFakeWingman() *FakeWingman {
+	return &FakeWingman{}
+}
+
+var explanationTemplates = []string{
+	"%s и %s отлично дополняют друг друга — у вас есть все, чтобы найти общий язык!",
+	"Похоже, %s и %s смотрят на мир похоже. Это может стать началом чего-то особенного.",
+	"У %s и %s совпадает больше, чем кажется на первый взгляд. Самое время познакомиться!",
+}
+
+var genericIcebreakers = []string{
+	"Привет, %s! Как проходит твоя неделя?",
+	"%s, если бы завтра был свободный день, как бы ты его провел(а)?",
+	"Привет, %s! Какое место в городе ты советуешь всем друзьям?",
+}
+
+func (w *FakeWingman) PromptVersion(subjectID int) string {
+	return FakePromptVersion
+}
+
+func (w *FakeWingman) MatchExplanation(ctx context.Context, version string, user1, user2 *Person) (string, error) {
+	shared := sharedInterests(user1, user2)
+	if len(shared) > 0 {
+		return fmt.Sprintf("%s и %s разделяют интерес к «%s» — отличная тема для первого разговора!",
+			user1.Name, user2.Name, strings.Join(shared, "», «")), nil
+	}
+
+	template := explanationTemplates[pick(len(explanationTemplates), user1.Name, user2.Name)]
+	return fmt.Sprintf(template, user1.Name, user2.Name), nil
+}
+
+func (w *FakeWingman) Icebreakers(ctx context.Context, version string, sender, recipient *Person) ([]string, error) {
+	const count = icebreakerCount
+	icebreakers := make([]string, 0, count)
+
+	for _, interest := range sharedInterests(sender, recipient) {
+		if len(icebreakers) == count {
+			break
+		}
+		icebreakers = append(icebreakers, fmt.Sprintf("Привет, %s! Мы оба любим «%s» — как ты этим увлекся(лась)?", recipient.Name, interest))
+	}
+	for _, interest := range recipient.Interests {
+		if len(icebreakers) == count {
+			break
+		}
+		if containsFold(sender.Interests, interest) {
+			continue
+		}
+		icebreakers = append(icebreakers, fmt.Sprintf("%s, вижу, тебе нравится «%s». Расскажешь, с чего начать новичку?", recipient.Name, interest))
+	}
+
+	start := pick(len(genericIcebreakers), sender.Name, recipient.Name)
+	for i := 0; len(icebreakers) < count && i < len(genericIcebreakers); i++ {
+		template := genericIcebreakers[(start+i)%len(genericIcebreakers)]
+		icebreakers = append(icebreakers, fmt.Sprintf(template, recipient.Name))
+	}
+
+	return icebreakers, nil
+}
+
+func (w *FakeWingman) BioSuggestions(ctx context.Context, version string, user *Person) ([]string, error) {
+	interests := "новые знакомства"
+	if len(user.Interests) > 0 {
+		interests = strings.Join(user.Interests, ", ")
+	}
+
+	mood := "Люблю спокойные вечера и долгие разговоры."
+	if user.Traits != nil && user.Traits.Extraversion >= 0.5 {
+		mood = "Всегда за движ и спонтанные поездки."
+	}
+
+	return []string{
+		fmt.Sprintf("Привет, я %s! Увлекаюсь: %s. %s", user.Name, interests, mood),
+		fmt.Sprintf("%s Ищу человека, с которым можно разделить %s.", mood, interests),
+		fmt.Sprintf("%s. Мои интересы: %s. Напиши, если совпадаем!", user.Name, interests),
+	}, nil
+}
+
+// sharedInterests returns interests of a that b also has, case insensitive
+func sharedInterests(a, b *Person) []string {
+	var shared []string
+	for _, interest := range a.Interests {
+		if containsFold(b.Interests, interest) {
+			shared = append(shared, interest)
+		}
+	}
+	return shared
+}
+
+func containsFold(items []string, s string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// pick deterministically chooses an index in [0, n) from the keys
+func pick(n int, keys ...string) int {
+	h := fnv.New32a()
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+	}
+	return int(h.Sum32() % uint32(n))
+}
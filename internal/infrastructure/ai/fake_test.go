@@ -0,0 +1,76 @@
+package ai
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+)
+
+func TestFakeWingman(t *testing.T) {
+	ctx := context.Background()
+	w := NewFakeWingman()
+
+	tests := []struct {
+		name   string
+		first  *Person
+		second *Person
+	}{
+		{
+			name:   "shared interests",
+			first:  &Person{Name: "Аня", Interests: []string{"Походы", "Кино", "Кофе", "Йога"}},
+			second: &Person{Name: "Олег", Interests: []string{"кино", "походы", "кофе", "йога"}},
+		},
+		{
+			name:   "different interests",
+			first:  &Person{Name: "Аня", Interests: []string{"Танцы"}, Traits: &domain.Traits{Extraversion: 0.8}},
+			second: &Person{Name: "Олег", Interests: []string{"Шахматы", "Рок"}},
+		},
+		{
+			name:   "no interests",
+			first:  &Person{Name: "Ян"},
+			second: &Person{Name: "Ия"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation, err := w.MatchExplanation(ctx, FakePromptVersion, tt.first, tt.second)
+			if err != nil {
+				t.Fatalf("MatchExplanation() error = %v", err)
+			}
+			if _, err := checkText("explanation", explanation, explanationMinLength, explanationMaxLength); err != nil {
+				t.Fatalf("MatchExplanation() = %q: %v", explanation, err)
+			}
+			again, _ := w.MatchExplanation(ctx, FakePromptVersion, tt.first, tt.second)
+			if again != explanation {
+				t.Fatalf("MatchExplanation() = %q, then %q for the same input", explanation, again)
+			}
+
+			icebreakers, err := w.Icebreakers(ctx, FakePromptVersion, tt.first, tt.second)
+			if err != nil {
+				t.Fatalf("Icebreakers() error = %v", err)
+			}
+			if _, err := checkList("icebreakers", icebreakers, icebreakerCount, icebreakerMinLength, icebreakerMaxLength); err != nil {
+				t.Fatalf("Icebreakers() = %q: %v", icebreakers, err)
+			}
+			again2, _ := w.Icebreakers(ctx, FakePromptVersion, tt.first, tt.second)
+			if !reflect.DeepEqual(again2, icebreakers) {
+				t.Fatalf("Icebreakers() = %q, then %q for the same input", icebreakers, again2)
+			}
+
+			bios, err := w.BioSuggestions(ctx, FakePromptVersion, tt.first)
+			if err != nil {
+				t.Fatalf("BioSuggestions() error = %v", err)
+			}
+			if _, err := checkList("bios", bios, bioCount, bioMinLength, bioMaxLength); err != nil {
+				t.Fatalf("BioSuggestions() = %q: %v", bios, err)
+			}
+			again3, _ := w.BioSuggestions(ctx, FakePromptVersion, tt.first)
+			if !reflect.DeepEqual(again3, bios) {
+				t.Fatalf("BioSuggestions() = %q, then %q for the same input", bios, again3)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+// defaultGeminiModel is used when no model is configured
+const defaultGeminiModel = "gemini-1.5-pro"
+
+// GeminiWingman is the AI Wingman backed by Google Gemini
+type GeminiWingman struct {
+	llmWingman
+	client *genai.Client
+	model  *genai.GenerativeModel
+}
+
+// NewGeminiWingman creates a Gemini client. Call Close to release it.
+func NewGeminiWingman(apiKey string, cfg Config) (*GeminiWingman, error) {
+	client, err := genai.NewClient(context.Background(), option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gemini client: %w", err)
+	}
+
+	modelName := cfg.Model
+	if modelName == "" {
+		modelName = defaultGeminiModel
+	}
+	model := client.GenerativeModel(modelName)
+	model.SetTemperature(float32(cfg.Temperature))
+	model.ResponseMIMEType = "application/json"
+
+	w := &GeminiWingman{
+		client: client,
+		model:  model,
+	}
+	w.llmWingman = llmWingman{complete: w.generate, timeout: cfg.Timeout, prompts: cfg.Prompts}
+	return w, nil
+}
+
+// Close releases the Gemini client
+func (w *GeminiWingman) Close() {
+	w.client.Close()
+}
+
+func (w *GeminiWingman) generate(ctx context.Context, prompt string) (string, error) {
+	resp, err := w.model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return "", fmt.Errorf("gemini request failed: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return "", errEmptyAnswer
+	}
+
+	var sb strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if txt, ok := part.(genai.Text); ok {
+			sb.WriteString(string(txt))
+		}
+	}
+
+	return strings.TrimSpace(sb.String()), nil
+}
@@ -0,0 +1,216 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/cache"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/ratelimit"
+)
+
+// GuardConfig configures caching and spending control of language model calls
+type GuardConfig struct {
+	// CacheTTL is how long generated texts are reused, zero disables the cache
+	CacheTTL time.Duration
+	// DailyBudget limits model calls per day across all users, zero means no limit
+	DailyBudget int
+	// UserDailyBudget limits model calls per day on behalf of a single user, zero means no limit
+	UserDailyBudget int
+	// BreakerThreshold is the number of consecutive failures that opens the circuit, zero disables the breaker
+	BreakerThreshold int
+	// BreakerCooldown is how long the fallback is used once the circuit is open
+	BreakerCooldown time.Duration
+}
+
+// GuardedWingman caches texts of the primary Wingman and limits its calls.
+// Texts are built by the fallback when the budget is spent or the primary keeps failing.
+type GuardedWingman struct {
+	primary  Wingman
+	fallback Wingman
+	store    cache.Store
+	cacheTTL time.Duration
+	global   *ratelimit.Limiter
+	perUser  *ratelimit.Limiter
+	breaker  *breaker
+}
+
+// NewGuardedWingman wraps the primary Wingman, the store keeps the cache and budget counters
+func NewGuardedWingman(primary, fallback Wingman, store cache.Store, cfg GuardConfig) *GuardedWingman {
+	return &GuardedWingman{
+		primary:  primary,
+		fallback: fallback,
+		store:    store,
+		cacheTTL: cfg.CacheTTL,
+		global:   ratelimit.NewLimiter(store, "ai_daily", cfg.DailyBudget, 24*time.Hour),
+		perUser:  ratelimit.NewLimiter(store, "ai_user_daily", cfg.UserDailyBudget, 24*time.Hour),
+		breaker:  newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+	}
+}
+
+func (w *GuardedWingman) PromptVersion(subjectID int) string {
+	return w.primary.PromptVersion(subjectID)
+}
+
+func (w *GuardedWingman) MatchExplanation(ctx context.Context, version string, user1, user2 *Person) (string, error) {
+	var explanation string
+	err := w.call(ctx, "explanation", version, []*Person{user1, user2}, &explanation,
+		func(wingman Wingman) error {
+			var err error
+			explanation, err = wingman.MatchExplanation(ctx, version, user1, user2)
+			return err
+		})
+	return explanation, err
+}
+
+func (w *GuardedWingman) Icebreakers(ctx context.Context, version string, sender, recipient *Person) ([]string, error) {
+	var icebreakers []string
+	// Only the sender is charged: the recipient gets their own icebreakers
+	err := w.call(ctx, "icebreakers", version, []*Person{sender, recipient}, &icebreakers,
+		func(wingman Wingman) error {
+			var err error
+			icebreakers, err = wingman.Icebreakers(ctx, version, sender, recipient)
+			return err
+		}, sender)
+	return icebreakers, err
+}
+
+func (w *GuardedWingman) BioSuggestions(ctx context.Context, version string, user *Person) ([]string, error) {
+	var bios []string
+	err := w.call(ctx, "bio", version, []*Person{user}, &bios,
+		func(wingman Wingman) error {
+			var err error
+			bios, err = wingman.BioSuggestions(ctx, version, user)
+			return err
+		})
+	return bios, err
+}
+
+// call serves the text from the cache or generates it with generate, which stores the text in out.
+// The call is charged to the budgets of payers, all users by default.
+// Errors of the primary are returned so the caller can retry, texts of the fallback are not cached.
+func (w *GuardedWingman) call(ctx context.Context, kind, version string, users []*Person, out interface{}, generate func(Wingman) error, payers ...*Person) error {
+	key := cacheKey(kind, version, users)
+	if w.cacheTTL > 0 {
+		value, ok, err := w.store.Get(ctx, key)
+		if err != nil {
+			fmt.Printf("⚠️  [AI Wingman] Failed to read cache: %v\n", err)
+		} else if ok && json.Unmarshal([]byte(value), out) == nil {
+			metricCacheHits.Add(1)
+			return nil
+		}
+	}
+
+	if len(payers) == 0 {
+		payers = users
+	}
+	if !w.breaker.allow() {
+		metricFallbacks.Add(1)
+		return generate(w.fallback)
+	}
+	if !w.withinBudget(ctx, payers) {
+		// The primary is not called, a half-open breaker lets the next call probe it
+		w.breaker.release()
+		metricFallbacks.Add(1)
+		return generate(w.fallback)
+	}
+
+	metricCalls.Add(1)
+	if err := generate(w.primary); err != nil {
+		metricErrors.Add(1)
+		w.breaker.failure()
+		return err
+	}
+	w.breaker.success()
+
+	if w.cacheTTL > 0 {
+		if value, err := json.Marshal(out); err == nil {
+			if err := w.store.Set(ctx, key, string(value), w.cacheTTL); err != nil {
+				fmt.Printf("⚠️  [AI Wingman] Failed to write cache: %v\n", err)
+			}
+		}
+	}
+	return nil
+}
+
+// withinBudget charges a call to the daily budgets of the payers and the global one.
+// If any budget is spent the call is refunded to all of them, so a rejected call costs nothing.
+// Budgets are not enforced while the store is unavailable.
+func (w *GuardedWingman) withinBudget(ctx context.Context, payers []*Person) bool {
+	var charged []*ratelimit.Result
+	refund := func() {
+		for _, result := range charged {
+			if err := w.perUser.Refund(ctx, result); err != nil {
+				fmt.Printf("⚠️  [AI Wingman] Failed to refund budget: %v\n", err)
+			}
+		}
+	}
+
+	for _, p := range payers {
+		result, err := w.perUser.Allow(ctx, strconv.Itoa(p.UserID))
+		if err != nil {
+			fmt.Printf("⚠️  [AI Wingman] Failed to check budget of user %d: %v\n", p.UserID, err)
+			continue
+		}
+		charged = append(charged, result)
+		if !result.Allowed {
+			refund()
+			return false
+		}
+	}
+
+	result, err := w.global.Allow(ctx, "global")
+	if err != nil {
+		fmt.Printf("⚠️  [AI Wingman] Failed to check daily budget: %v\n", err)
+		return true
+	}
+	if !result.Allowed {
+		fmt.Printf("⚠️  [AI Wingman] Daily budget of %d calls is spent, using fallback texts\n", result.Limit)
+		refund()
+		if err := w.global.Refund(ctx, result); err != nil {
+			fmt.Printf("⚠️  [AI Wingman] Failed to refund daily budget: %v\n", err)
+		}
+	}
+	return result.Allowed
+}
+
+// personKey is the normalized part of a person that reaches the prompt
+type personKey struct {
+	Name      string   `json:"name"`
+	Bio       string   `json:"bio"`
+	Interests []string `json:"interests"`
+	Traits    string   `json:"traits"`
+}
+
+// cacheKey hashes normalized inputs of the prompt, so users with the same data
+// (case and order of interests, exact trait scores don't matter) share the text
+func cacheKey(kind, version string, users []*Person) string {
+	keys := make([]personKey, 0, len(users))
+	for _, u := range users {
+		k := personKey{
+			Name: strings.TrimSpace(u.Name),
+			Bio:  strings.Join(strings.Fields(u.Bio), " "),
+		}
+		for _, interest := range u.Interests {
+			if interest = strings.ToLower(strings.TrimSpace(interest)); interest != "" {
+				k.Interests = append(k.Interests, interest)
+			}
+		}
+		sort.Strings(k.Interests)
+		// The prompt only sees trait levels
+		if u.Traits != nil {
+			k.Traits = describeTraits(u.Traits)
+		}
+		keys = append(keys, k)
+	}
+
+	data, _ := json.Marshal(keys)
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("ai:%s:%s:%s", kind, version, hex.EncodeToString(sum[:]))
+}
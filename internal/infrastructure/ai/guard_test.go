@@ -0,0 +1,47 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/cache"
+)
+
+func TestWithinBudgetRefundsRejectedCalls(t *testing.T) {
+	ctx := context.Background()
+	alice, bob := &Person{UserID: 1}, &Person{UserID: 2}
+
+	t.Run("user budget spent", func(t *testing.T) {
+		w := NewGuardedWingman(nil, nil, cache.NewMemoryStore(), GuardConfig{DailyBudget: 10, UserDailyBudget: 1})
+
+		if !w.withinBudget(ctx, []*Person{alice}) {
+			t.Fatal("first call of alice is rejected")
+		}
+		// Bob is charged first, then alice's spent budget rejects the call
+		if w.withinBudget(ctx, []*Person{bob, alice}) {
+			t.Fatal("call with alice's spent budget is allowed")
+		}
+		if !w.withinBudget(ctx, []*Person{bob}) {
+			t.Fatal("bob is charged for a rejected call")
+		}
+	})
+
+	t.Run("global budget spent", func(t *testing.T) {
+		w := NewGuardedWingman(nil, nil, cache.NewMemoryStore(), GuardConfig{DailyBudget: 1, UserDailyBudget: 1})
+
+		if !w.withinBudget(ctx, []*Person{alice}) {
+			t.Fatal("first call is rejected")
+		}
+		if w.withinBudget(ctx, []*Person{bob}) {
+			t.Fatal("call over the global budget is allowed")
+		}
+
+		result, err := w.perUser.Allow(ctx, "2")
+		if err != nil {
+			t.Fatalf("Allow() error = %v", err)
+		}
+		if !result.Allowed {
+			t.Fatal("bob is charged for a call rejected by the global budget")
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package ai
+
+import "expvar"
+
+// Counters of guarded AI Wingman calls, published at /debug/vars as "ai_wingman"
+var (
+	// metricCalls counts requests sent to the language model
+	metricCalls = new(expvar.Int)
+	// metricCacheHits counts texts served from the cache
+	metricCacheHits = new(expvar.Int)
+	// metricFallbacks counts texts built by the fallback generator (open circuit or exhausted budget)
+	metricFallbacks = new(expvar.Int)
+	// metricErrors counts failed requests to the language model
+	metricErrors = new(expvar.Int)
+)
+
+func init() {
+	metrics := expvar.NewMap("ai_wingman")
+	metrics.Set("calls", metricCalls)
+	metrics.Set("cache_hits", metricCacheHits)
+	metrics.Set("fallbacks", metricFallbacks)
+	metrics.Set("errors", metricErrors)
+}
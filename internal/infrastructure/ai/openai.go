@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OpenAIWingman is the AI Wingman backed by an OpenAI-compatible chat completions API,
+// e.g. a self-hosted model behind vLLM, llama.cpp or Ollama
+type OpenAIWingman struct {
+	llmWingman
+	httpClient  *http.Client
+	baseURL     string
+	apiKey      string
+	model       string
+	temperature float64
+}
+
+// NewOpenAIWingman creates a client of the API at baseURL (e.g. http://localhost:8000/v1),
+// apiKey may be empty for servers without authentication
+func NewOpenAIWingman(baseURL, apiKey string, cfg Config) *OpenAIWingman {
+	w := &OpenAIWingman{
+		// Requests are limited by the context, see llmWingman.ask
+		httpClient:  &http.Client{},
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		apiKey:      apiKey,
+		model:       cfg.Model,
+		temperature: cfg.Temperature,
+	}
+	w.llmWingman = llmWingman{complete: w.chat, timeout: cfg.Timeout, prompts: cfg.Prompts}
+	return w
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+type chatRequest struct {
+	Model          string         `json:"model"`
+	Messages       []chatMessage  `json:"messages"`
+	Temperature    float64        `json:"temperature"`
+	ResponseFormat responseFormat `json:"response_format"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (w *OpenAIWingman) chat(ctx context.Context, prompt string) (string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model:       w.model,
+		Messages:    []chatMessage{{Role: "user", Content: prompt}},
+		Temperature: w.temperature,
+		// Prompts ask for a JSON object, JSON mode makes the server enforce it
+		ResponseFormat: responseFormat{Type: "json_object"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+w.apiKey)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call model API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result chatResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response (status %d): %w", resp.StatusCode, err)
+	}
+	if result.Error != nil {
+		return "", fmt.Errorf("model API error (status %d): %s", resp.StatusCode, result.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("model API returned status %d", resp.StatusCode)
+	}
+	if len(result.Choices) == 0 {
+		return "", errEmptyAnswer
+	}
+
+	return strings.TrimSpace(result.Choices[0].Message.Content), nil
+}
@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newOpenAIServer serves chat completions with the given status and body,
+// the last request is stored in req
+func newOpenAIServer(t *testing.T, status int, body string, req *chatRequest) *OpenAIWingman {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, `{"error": {"message": "bad headers"}}`, http.StatusUnauthorized)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, `{"error": {"message": "bad body"}}`, http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	prompts, err := LoadPrompts([]string{"v1"})
+	if err != nil {
+		t.Fatalf("LoadPrompts() error = %v", err)
+	}
+	return NewOpenAIWingman(server.URL+"/v1/", "secret", Config{Model: "local-model", Temperature: 0.7, Prompts: prompts})
+}
+
+// chatAnswer is a completion response with the content
+func chatAnswer(content string) string {
+	data, _ := json.Marshal(map[string]interface{}{
+		"choices": []map[string]interface{}{
+			{"message": map[string]string{"role": "assistant", "content": content}},
+		},
+	})
+	return string(data)
+}
+
+func TestOpenAIWingmanRequest(t *testing.T) {
+	var req chatRequest
+	w := newOpenAIServer(t, http.StatusOK, chatAnswer(`{"explanation": "Вы оба любите походы и горы, есть о чем поговорить!"}`), &req)
+
+	explanation, err := w.MatchExplanation(context.Background(), "v1", &Person{Name: "Аня"}, &Person{Name: "Олег"})
+	if err != nil {
+		t.Fatalf("MatchExplanation() error = %v", err)
+	}
+	if explanation != "Вы оба любите походы и горы, есть о чем поговорить!" {
+		t.Fatalf("MatchExplanation() = %q", explanation)
+	}
+
+	if req.Model != "local-model" || req.Temperature != 0.7 {
+		t.Fatalf("request model %q, temperature %v", req.Model, req.Temperature)
+	}
+	if req.ResponseFormat.Type != "json_object" {
+		t.Fatalf("request response_format %q, want json_object", req.ResponseFormat.Type)
+	}
+	if len(req.Messages) != 1 || req.Messages[0].Role != "user" || !strings.Contains(req.Messages[0].Content, "Аня") {
+		t.Fatalf("request messages %+v, want the rendered prompt as a user message", req.Messages)
+	}
+}
+
+func TestOpenAIWingmanResponses(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+		errText string
+	}{
+		{
+			name:   "JSON in a code block",
+			status: http.StatusOK,
+			body:   chatAnswer("```json\n{\"icebreakers\": [\"Привет! Как дела?\", \"Что читаешь сейчас?\", \"Куда бы поехал(а) летом?\"]}\n```"),
+		},
+		{
+			name:    "answer not matching the schema",
+			status:  http.StatusOK,
+			body:    chatAnswer(`{"icebreakers": ["Привет! Как дела?"], "extra": true}`),
+			wantErr: ErrInvalidOutput,
+		},
+		{
+			name:    "unsafe answer",
+			status:  http.StatusOK,
+			body:    chatAnswer(`{"icebreakers": ["Привет! Как дела?", "Напиши мне в телеграм", "Куда бы поехал(а) летом?"]}`),
+			wantErr: ErrUnsafeOutput,
+		},
+		{
+			name:    "no choices",
+			status:  http.StatusOK,
+			body:    `{"choices": []}`,
+			wantErr: errEmptyAnswer,
+		},
+		{
+			name:    "error object",
+			status:  http.StatusTooManyRequests,
+			body:    `{"error": {"message": "rate limit reached"}}`,
+			errText: "model API error (status 429): rate limit reached",
+		},
+		{
+			name:    "error status without error object",
+			status:  http.StatusBadGateway,
+			body:    `{}`,
+			errText: "model API returned status 502",
+		},
+		{
+			name:    "not JSON",
+			status:  http.StatusServiceUnavailable,
+			body:    "upstream unavailable",
+			errText: "failed to parse response (status 503)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req chatRequest
+			w := newOpenAIServer(t, tt.status, tt.body, &req)
+
+			icebreakers, err := w.Icebreakers(context.Background(), "v1", &Person{Name: "Аня"}, &Person{Name: "Олег"})
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Icebreakers() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.errText != "":
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("Icebreakers() error = %v, want %q", err, tt.errText)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("Icebreakers() error = %v", err)
+				}
+				if len(icebreakers) != icebreakerCount {
+					t.Fatalf("Icebreakers() = %q", icebreakers)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Output limits, checked for every model answer
+const (
+	explanationMinLength = 20
+	explanationMaxLength = 300
+	icebreakerCount      = 3
+	icebreakerMinLength  = 5
+	icebreakerMaxLength  = 200
+	bioCount             = 3
+	bioMinLength         = 20
+	bioMaxLength         = 300
+)
+
+var (
+	errEmptyAnswer = errors.New("model returned an empty answer")
+	// ErrInvalidOutput means the model answer doesn't match the expected schema
+	ErrInvalidOutput = errors.New("invalid model output")
+	// ErrUnsafeOutput means the model answer failed the safety filter
+	ErrUnsafeOutput = errors.New("unsafe model output")
+)
+
+type explanationOutput struct {
+	Explanation string `json:"explanation"`
+}
+
+type icebreakersOutput struct {
+	Icebreakers []string `json:"icebreakers"`
+}
+
+type bioOutput struct {
+	Bios []string `json:"bios"`
+}
+
+// decodeOutput parses the JSON object answer into out, unknown fields are rejected
+func decodeOutput(text string, out interface{}) error {
+	text = strings.TrimSpace(text)
+	// Clean up markdown code blocks if present
+	text = strings.TrimPrefix(text, "```json")
+	text = strings.TrimPrefix(text, "```")
+	text = strings.TrimSuffix(text, "```")
+
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(out); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+	}
+	return nil
+}
+
+// checkText validates the length of a single generated text and runs the safety filter
+func checkText(field, text string, minLength, maxLength int) (string, error) {
+	text = strings.TrimSpace(text)
+	length := utf8.RuneCountInString(text)
+	if length < minLength || length > maxLength {
+		return "", fmt.Errorf("%w: %s has %d characters, expected %d-%d", ErrInvalidOutput, field, length, minLength, maxLength)
+	}
+	if reason := unsafeReason(text); reason != "" {
+		return "", fmt.Errorf("%w: %s %s", ErrUnsafeOutput, field, reason)
+	}
+	return text, nil
+}
+
+// checkList validates a list of exactly count generated texts
+func checkList(field string, items []string, count, minLength, maxLength int) ([]string, error) {
+	if len(items) != count {
+		return nil, fmt.Errorf("%w: expected %d %s, got %d", ErrInvalidOutput, count, field, len(items))
+	}
+
+	result := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		text, err := checkText(field, item, minLength, maxLength)
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(text)
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicate %s", ErrInvalidOutput, field)
+		}
+		seen[key] = true
+		result = append(result, text)
+	}
+	return result, nil
+}
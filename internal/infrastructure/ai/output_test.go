@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "plain JSON", text: `{"icebreakers": ["a", "b"]}`, want: []string{"a", "b"}},
+		{name: "surrounding whitespace", text: "\n  {\"icebreakers\": [\"a\"]}  \n", want: []string{"a"}},
+		{name: "json code fence", text: "```json\n{\"icebreakers\": [\"a\"]}\n```", want: []string{"a"}},
+		{name: "bare code fence", text: "```\n{\"icebreakers\": [\"a\"]}\n```", want: []string{"a"}},
+		{name: "unknown field", text: `{"icebreakers": ["a"], "comment": "x"}`, wantErr: true},
+		{name: "wrong type", text: `{"icebreakers": "a"}`, wantErr: true},
+		{name: "not JSON", text: "Вот ваши фразы: a, b", wantErr: true},
+		{name: "empty", text: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out icebreakersOutput
+			err := decodeOutput(tt.text, &out)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidOutput) {
+					t.Fatalf("decodeOutput() error = %v, want %v", err, ErrInvalidOutput)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeOutput() error = %v", err)
+			}
+			if !reflect.DeepEqual(out.Icebreakers, tt.want) {
+				t.Fatalf("decodeOutput() = %q, want %q", out.Icebreakers, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr error
+	}{
+		{name: "valid", text: "Привет, как дела?", want: "Привет, как дела?"},
+		{name: "trimmed", text: "  Привет, как дела?\n", want: "Привет, как дела?"},
+		{name: "minimum length in characters", text: "Приве", want: "Приве"},
+		{name: "maximum length in characters", text: strings.Repeat("я", 20), want: strings.Repeat("я", 20)},
+		{name: "too short", text: "При", wantErr: ErrInvalidOutput},
+		{name: "too short after trimming", text: "   Пр   ", wantErr: ErrInvalidOutput},
+		{name: "too long", text: strings.Repeat("я", 21), wantErr: ErrInvalidOutput},
+		{name: "unsafe", text: "Пиши в телеграм", wantErr: ErrUnsafeOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkText("icebreakers", tt.text, 5, 20)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("checkText() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkText() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("checkText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckList(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []string
+		want    []string
+		wantErr error
+	}{
+		{name: "valid", items: []string{"Привет!", " Как дела? ", "Что читаешь?"}, want: []string{"Привет!", "Как дела?", "Что читаешь?"}},
+		{name: "too few", items: []string{"Привет!", "Как дела?"}, wantErr: ErrInvalidOutput},
+		{name: "too many", items: []string{"Привет!", "Как дела?", "Что читаешь?", "Куда едешь?"}, wantErr: ErrInvalidOutput},
+		{name: "empty", items: nil, wantErr: ErrInvalidOutput},
+		{name: "duplicate", items: []string{"Привет!", "Как дела?", "привет!"}, wantErr: ErrInvalidOutput},
+		{name: "duplicate after trimming", items: []string{"Привет!", "Как дела?", " Привет! "}, wantErr: ErrInvalidOutput},
+		{name: "item too long", items: []string{"Привет!", "Как дела?", strings.Repeat("я", 21)}, wantErr: ErrInvalidOutput},
+		{name: "unsafe item", items: []string{"Привет!", "Как дела?", "Тел 89991234567"}, wantErr: ErrUnsafeOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkList("icebreakers", tt.items, 3, 5, 20)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("checkList() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkList() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("checkList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package ai
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+)
+
+// Prompt templates are stored in prompts/<version>/<name>.tmpl.
+// A new version is a new directory, old versions are kept so stored results stay explainable.
+//
+//go:embed prompts
+var promptFiles embed.FS
+
+// Prompt template names
+const (
+	promptExplanation = "explanation.tmpl"
+	promptIcebreakers = "icebreakers.tmpl"
+	promptBio         = "bio.tmpl"
+)
+
+var promptFuncs = template.FuncMap{
+	"join":           strings.Join,
+	"describeTraits": describeTraits,
+}
+
+// Prompts holds the versioned prompt templates
+type Prompts struct {
+	sets map[string]*template.Template
+	// active are the versions used for new texts, split evenly between subjects
+	active []string
+}
+
+// LoadPrompts parses the embedded prompt templates, active are the versions to A/B test
+func LoadPrompts(active []string) (*Prompts, error) {
+	dirs, err := fs.ReadDir(promptFiles, "prompts")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompts: %w", err)
+	}
+
+	p := &Prompts{sets: make(map[string]*template.Template)}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		version := dir.Name()
+		set, err := template.New(version).Funcs(promptFuncs).ParseFS(promptFiles, path.Join("prompts", version, "*.tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse prompts %s: %w", version, err)
+		}
+		for _, name := range []string{promptExplanation, promptIcebreakers, promptBio} {
+			if set.Lookup(name) == nil {
+				return nil, fmt.Errorf("prompts %s: missing %s", version, name)
+			}
+		}
+		p.sets[version] = set
+	}
+
+	if len(active) == 0 {
+		return nil, fmt.Errorf("no active prompt versions")
+	}
+	for _, version := range active {
+		if _, ok := p.sets[version]; !ok {
+			return nil, fmt.Errorf("unknown prompt version %q, available: %s", version, strings.Join(p.Versions(), ", "))
+		}
+	}
+	p.active = active
+
+	return p, nil
+}
+
+// Versions returns all embedded prompt versions
+func (p *Prompts) Versions() []string {
+	versions := make([]string, 0, len(p.sets))
+	for version := range p.sets {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// Pick returns the active version for the subject (a match or a user), the same subject always gets the same version
+func (p *Prompts) Pick(subjectID int) string {
+	if subjectID < 0 {
+		subjectID = -subjectID
+	}
+	return p.active[subjectID%len(p.active)]
+}
+
+// render executes the named template of the version
+func (p *Prompts) render(version, name string, data interface{}) (string, error) {
+	set, ok := p.sets[version]
+	if !ok {
+		return "", fmt.Errorf("unknown prompt version %q", version)
+	}
+
+	var buf bytes.Buffer
+	if err := set.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt %s/%s: %w", version, name, err)
+	}
+	return buf.String(), nil
+}
+
+// describeTraits turns Big Five scores into words, raw scores are private and never sent to the model
+func describeTraits(t *domain.Traits) string {
+	level := func(v float64) string {
+		switch {
+		case v >= 0.67:
+			return "high"
+		case v <= 0.33:
+			return "low"
+		}
+		return "medium"
+	}
+	return fmt.Sprintf("%s openness, %s conscientiousness, %s extraversion, %s agreeableness, %s emotional sensitivity",
+		level(t.Openness), level(t.Conscientiousness), level(t.Extraversion), level(t.Agreeableness), level(t.Neuroticism))
+}
+
+// explanationData is the data of the explanation prompt
+type explanationData struct {
+	User1, User2 *Person
+	MaxLength    int
+}
+
+// icebreakersData is the data of the icebreakers prompt
+type icebreakersData struct {
+	Sender, Recipient *Person
+	Count, MaxLength  int
+}
+
+// bioData is the data of the bio prompt
+type bioData struct {
+	User             *Person
+	Count, MaxLength int
+}
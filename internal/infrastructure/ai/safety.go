@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}`)
+	// Seven or more digits, possibly separated by spaces, dashes, dots or brackets
+	phonePattern = regexp.MustCompile(`\+?\d(?:[\s\-().]*\d){6,}`)
+	linkPattern  = regexp.MustCompile(`(?i)(https?://|www\.|\b[a-z0-9\-]+\.(ru|com|net|org|me|io|su)\b|\.рф)`)
+	// @username, but not an e-mail
+	handlePattern = regexp.MustCompile(`(^|\s)@[A-Za-z0-9_]{3,}`)
+	// Numbers with a fraction or a percent look like test scores
+	scorePattern = regexp.MustCompile(`\d+[.,]\d+|\d+\s?%`)
+)
+
+// contactPhrases point to moving the conversation outside the app
+var contactPhrases = []string{
+	"telegram", "телеграм", "телегу", "телеге", "whatsapp", "ватсап", "вотсап", "viber", "вайбер",
+	"instagram", "инстаграм", "инсту", "snapchat", "vk.com", "номер телефона", "мой номер", "твой номер",
+}
+
+// privatePhrases refer to personality test results, they are never shown to the other user
+var privatePhrases = []string{
+	"big five", "тест личности", "результат теста", "результатам теста", "по тесту",
+	"нейротизм", "невротизм", "neuroticism", "психотип",
+}
+
+// privateWords are whole words with the same meaning as privatePhrases
+var privateWords = []string{
+	"балл", "балла", "баллов", "баллы",
+}
+
+// slurRoots are beginnings of insulting and obscene words
+var slurRoots = []string{
+	"хуй", "хуе", "хуё", "пизд", "ебан", "ебат", "ебал", "ёбан", "уеб", "уёб", "бляд", "блят",
+	"сука", "суки", "сукин", "мудак", "мудил", "пидор", "пидар", "шлюх", "чурк", "дебил", "идиот", "урод",
+	"fuck", "bitch", "whore", "slut", "faggot", "nigg", "retard", "cunt",
+}
+
+// unsafeReason returns why the text can't be shown, empty if it's safe.
+// Texts must not contain contacts, insults or personality test results.
+func unsafeReason(text string) string {
+	lower := strings.ToLower(text)
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	switch {
+	case emailPattern.MatchString(text):
+		return "contains an e-mail"
+	case phonePattern.MatchString(text):
+		return "contains a phone number"
+	case linkPattern.MatchString(text):
+		return "contains a link"
+	case handlePattern.MatchString(text):
+		return "contains a username"
+	case containsAny(lower, contactPhrases):
+		return "asks to move to another messenger"
+	case hasWordPrefix(words, slurRoots):
+		return "contains offensive language"
+	case scorePattern.MatchString(text), containsAny(lower, privatePhrases), hasWord(words, privateWords):
+		return "mentions private data"
+	}
+	return ""
+}
+
+func containsAny(s string, phrases []string) bool {
+	for _, phrase := range phrases {
+		if strings.Contains(s, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasWord(words, targets []string) bool {
+	for _, word := range words {
+		for _, target := range targets {
+			if word == target {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasWordPrefix(words, roots []string) bool {
+	for _, word := range words {
+		for _, root := range roots {
+			if strings.HasPrefix(word, root) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package ai
+
+import "testing"
+
+func TestUnsafeReason(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "e-mail", text: "Пиши на anna.k@mail.ru", want: "contains an e-mail"},
+		{name: "phone", text: "Звони +7 (999) 123-45-67", want: "contains a phone number"},
+		{name: "phone without separators", text: "мой 89991234567", want: "contains a phone number"},
+		{name: "http link", text: "Смотри http://example.org/page", want: "contains a link"},
+		{name: "www link", text: "Зайди на www.site", want: "contains a link"},
+		{name: "bare domain", text: "Мой блог anna.me", want: "contains a link"},
+		{name: "cyrillic domain", text: "Заходи на сайт.рф", want: "contains a link"},
+		{name: "handle", text: "Добавляйся @anna_k", want: "contains a username"},
+		{name: "handle at the start", text: "@anna_k пиши", want: "contains a username"},
+		{name: "telegram", text: "Давай продолжим в Телеграм?", want: "asks to move to another messenger"},
+		{name: "whatsapp", text: "Напиши в WhatsApp", want: "asks to move to another messenger"},
+		{name: "asks for number", text: "Дай свой номер телефона", want: "asks to move to another messenger"},
+		{name: "slur", text: "Ты идиот", want: "contains offensive language"},
+		{name: "slur in another case", text: "ДЕБИЛЬНЫЙ вопрос", want: "contains offensive language"},
+		{name: "english slur", text: "What the fuck", want: "contains offensive language"},
+		{name: "fractional score", text: "Твоя экстраверсия 0.8", want: "mentions private data"},
+		{name: "percent", text: "Совпадение 87 %", want: "mentions private data"},
+		{name: "score in words", text: "У тебя 8 баллов открытости", want: "mentions private data"},
+		{name: "big five", text: "Судя по Big Five, вы похожи", want: "mentions private data"},
+		{name: "test results", text: "По результатам теста вы пара", want: "mentions private data"},
+		{name: "neuroticism", text: "Низкий нейротизм у обоих", want: "mentions private data"},
+
+		// False positives the filter must let through
+		{name: "plain greeting", text: "Привет! Как прошла неделя?"},
+		{name: "word starting like a slur root", text: "Сукно и шерсть — мои любимые ткани"},
+		{name: "slur root inside a word", text: "На скалодроме всегда страхуйся"},
+		{name: "small number", text: "Пробежал 10 км за 50 минут"},
+		{name: "year", text: "В 2024 году я был в Казани"},
+		{name: "time", text: "Встретимся в 19:30?"},
+		{name: "ball is not a score", text: "Пойдем на бал или на балет?"},
+		{name: "sentence end before a word", text: "Люблю кино.Ты тоже?"},
+		{name: "at sign inside text", text: "Встреча в кафе@центр"},
+		{name: "number word", text: "Какой номер в рейтинге у твоего любимого фильма?"},
+		{name: "english", text: "Let's grab a coffee sometime"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unsafeReason(tt.text); got != tt.want {
+				t.Fatalf("unsafeReason(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
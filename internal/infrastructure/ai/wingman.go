@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"context"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+)
+
+// Person is what the AI Wingman knows about a user
+type Person struct {
+	// UserID is used for per-user budgets, it is never sent to the model
+	UserID    int
+	Name      string
+	Bio       string
+	Interests []string
+	// Traits are the measured Big Five traits, nil if the user didn't take the test
+	Traits *domain.Traits
+}
+
+// NewPerson describes the owner of the profile, traits may be nil
+func NewPerson(profile *domain.Profile, traits *domain.Traits) *Person {
+	p := &Person{
+		UserID:    profile.UserID,
+		Name:      profile.DisplayName,
+		Interests: profile.Interests,
+		Traits:    traits,
+	}
+	if profile.Bio != nil {
+		p.Bio = *profile.Bio
+	}
+	return p
+}
+
+// Wingman generates texts that help users get acquainted.
+// Texts are generated with a prompt version, PromptVersion splits subjects between versions for A/B tests.
+type Wingman interface {
+	// PromptVersion returns the prompt version for the subject (a match or a user),
+	// the same subject always gets the same version
+	PromptVersion(subjectID int) string
+	// MatchExplanation explains in a couple of sentences why the users fit each other
+	MatchExplanation(ctx context.Context, version string, user1, user2 *Person) (string, error)
+	// Icebreakers returns opening lines for the sender to send to the recipient
+	Icebreakers(ctx context.Context, version string, sender, recipient *Person) ([]string, error)
+	// BioSuggestions returns variants of the user's profile bio
+	BioSuggestions(ctx context.Context, version string, user *Person) ([]string, error)
+}
+
+// Config configures the language model
+type Config struct {
+	// Model is the model name, empty means the provider default
+	Model       string
+	Temperature float64
+	// Timeout limits a single request to the model
+	Timeout time.Duration
+	Prompts *Prompts
+}
+
+// completeFunc sends a prompt to a language model and returns the text answer, a JSON object
+type completeFunc func(ctx context.Context, prompt string) (string, error)
+
+// llmWingman implements Wingman on top of a language model.
+// Answers are validated against the output schema and the safety filter.
+type llmWingman struct {
+	complete completeFunc
+	timeout  time.Duration
+	prompts  *Prompts
+}
+
+func (w *llmWingman) PromptVersion(subjectID int) string {
+	return w.prompts.Pick(subjectID)
+}
+
+func (w *llmWingman) MatchExplanation(ctx context.Context, version string, user1, user2 *Person) (string, error) {
+	var out explanationOutput
+	err := w.ask(ctx, version, promptExplanation, explanationData{
+		User1:     user1,
+		User2:     user2,
+		MaxLength: explanationMaxLength,
+	}, &out)
+	if err != nil {
+		return "", err
+	}
+	return checkText("explanation", out.Explanation, explanationMinLength, explanationMaxLength)
+}
+
+func (w *llmWingman) Icebreakers(ctx context.Context, version string, sender, recipient *Person) ([]string, error) {
+	var out icebreakersOutput
+	err := w.ask(ctx, version, promptIcebreakers, icebreakersData{
+		Sender:    sender,
+		Recipient: recipient,
+		Count:     icebreakerCount,
+		MaxLength: icebreakerMaxLength,
+	}, &out)
+	if err != nil {
+		return nil, err
+	}
+	return checkList("icebreakers", out.Icebreakers, icebreakerCount, icebreakerMinLength, icebreakerMaxLength)
+}
+
+func (w *llmWingman) BioSuggestions(ctx context.Context, version string, user *Person) ([]string, error) {
+	var out bioOutput
+	err := w.ask(ctx, version, promptBio, bioData{
+		User:      user,
+		Count:     bioCount,
+		MaxLength: bioMaxLength,
+	}, &out)
+	if err != nil {
+		return nil, err
+	}
+	return checkList("bios", out.Bios, bioCount, bioMinLength, bioMaxLength)
+}
+
+// ask renders the prompt, sends it with the configured timeout and decodes the answer into out
+func (w *llmWingman) ask(ctx context.Context, version, name string, data, out interface{}) error {
+	prompt, err := w.prompts.render(version, name, data)
+	if err != nil {
+		return err
+	}
+
+	if w.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.timeout)
+		defer cancel()
+	}
+
+	text, err := w.complete(ctx, prompt)
+	if err != nil {
+		return err
+	}
+	return decodeOutput(text, out)
+}
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memoryItem is a stored value with its expiration time
+type memoryItem struct {
+	value     string
+	expiresAt time.Time
+}
+
+// MemoryStore is a Store kept in process memory.
+// Used for single-node deployments without Redis.
+type MemoryStore struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+	// sets counts writes since the last cleanup of expired items
+	sets int
+}
+
+// NewMemoryStore creates a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		items: make(map[string]memoryItem),
+	}
+}
+
+func (s *MemoryStore) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if item, ok := s.items[key]; ok && now.Before(item.expiresAt) {
+		return false, nil
+	}
+
+	s.items[key] = memoryItem{value: value, expiresAt: now.Add(ttl)}
+	s.cleanup(now)
+	return true, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[key]
+	if !ok || !time.Now().Before(item.expiresAt) {
+		return "", false, nil
+	}
+	return item.value, true, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.items[key] = memoryItem{value: value, expiresAt: now.Add(ttl)}
+	s.cleanup(now)
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.items, key)
+	return nil
+}
+
+func (s *MemoryStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var count int64
+	if item, ok := s.items[key]; ok && now.Before(item.expiresAt) {
+		parsed, err := strconv.ParseInt(item.value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value of %s is not a counter", key)
+		}
+		count = parsed
+	}
+	count++
+
+	s.items[key] = memoryItem{value: strconv.FormatInt(count, 10), expiresAt: now.Add(ttl)}
+	s.cleanup(now)
+	return count, nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, key string, ttl time.Duration, fn UpdateFunc) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	item, exists := s.items[key]
+	if exists && !now.Before(item.expiresAt) {
+		item, exists = memoryItem{}, false
+	}
+
+	value, keep, err := fn(item.value, exists)
+	if err != nil {
+		return err
+	}
+	if !keep {
+		delete(s.items, key)
+		return nil
+	}
+
+	s.items[key] = memoryItem{value: value, expiresAt: now.Add(ttl)}
+	s.cleanup(now)
+	return nil
+}
+
+// cleanup drops expired items every 1000 writes so the map doesn't grow forever
+func (s *MemoryStore) cleanup(now time.Time) {
+	s.sets++
+	if s.sets < 1000 {
+		return
+	}
+	s.sets = 0
+
+	for key, item := range s.items {
+		if !now.Before(item.expiresAt) {
+			delete(s.items, key)
+		}
+	}
+}
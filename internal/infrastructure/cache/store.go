@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Store is a key-value store with expiration shared by all server instances
+type Store interface {
+	// SetNX sets the key only if it doesn't exist and reports whether it was set
+	SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)
+	// Get returns the value of the key and reports whether it exists
+	Get(ctx context.Context, key string) (string, bool, error)
+	// Set sets the key overwriting the previous value
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	// Delete removes the key, missing keys are ignored
+	Delete(ctx context.Context, key string) error
+	// Incr increments the counter (a missing key counts from 0), sets its ttl and returns the new value
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Update atomically replaces the value of the key with the result of fn.
+	// fn gets the current value (exists is false for a missing key) and returns the new value
+	// and whether to keep the key, the key is deleted otherwise.
+	// fn may be called again if the key changes concurrently and must not use the store.
+	Update(ctx context.Context, key string, ttl time.Duration, fn UpdateFunc) error
+}
+
+// UpdateFunc computes the new value of a key, see Store.Update
+type UpdateFunc func(value string, exists bool) (newValue string, keep bool, err error)
@@ -2,31 +2,48 @@ package container
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/config"
 	"github.com/gdugdh24/mpit2026-backend/internal/delivery/http"
 	"github.com/gdugdh24/mpit2026-backend/internal/delivery/http/handler"
 	"github.com/gdugdh24/mpit2026-backend/internal/delivery/http/middleware"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/ai"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/cache"
 	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/database"
-	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/gemini"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/jobs"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/ratelimit"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/realtime"
 	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/server"
 	"github.com/gdugdh24/mpit2026-backend/internal/repository/postgres"
 	"github.com/gdugdh24/mpit2026-backend/internal/usecase/auth"
 	"github.com/gdugdh24/mpit2026-backend/internal/usecase/bigfive"
 	"github.com/gdugdh24/mpit2026-backend/internal/usecase/feed"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/match"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/message"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/notification"
 	"github.com/gdugdh24/mpit2026-backend/internal/usecase/profile"
 	"github.com/gdugdh24/mpit2026-backend/internal/usecase/swipe"
+	"github.com/gdugdh24/mpit2026-backend/pkg/jwt"
+	"github.com/gdugdh24/mpit2026-backend/pkg/vkapi"
 	"github.com/jmoiron/sqlx"
 	"github.com/redis/go-redis/v9"
 )
 
 // Container holds all application dependencies
 type Container struct {
-	Config *config.Config
-	DB     *sqlx.DB
-	Redis  *redis.Client
-	Server *server.Server
-	Gemini *gemini.GeminiClient
+	Config   *config.Config
+	DB       *sqlx.DB
+	Redis    *redis.Client
+	Server   *server.Server
+	Gemini   *ai.GeminiWingman
+	Broker   realtime.Broker
+	Hub      *realtime.Hub
+	Notifier *notification.Notifier
+	Pusher   *notification.Pusher
+	Cleaner  *auth.SessionCleaner
+	Detector *swipe.MassLikerDetector
+	Jobs     *jobs.Queue
 }
 
 // NewContainer creates a new dependency injection container
@@ -37,17 +54,70 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 
-	// // Initialize Redis
-	// redisClient, err := database.NewRedisClient(&cfg.Redis)
-	// if err != nil {
-	// 	return nil, fmt.Errorf("failed to initialize redis: %w", err)
-	// }
+	// Initialize Redis (optional, in-memory implementations are used without it)
+	var redisClient *redis.Client
+	if cfg.Redis.Host != "" {
+		redisClient, err = database.NewRedisClient(&cfg.Redis)
+		if err != nil {
+			fmt.Printf("Warning: Failed to initialize Redis, falling back to in-memory: %v\n", err)
+			redisClient = nil
+		}
+	}
+
+	// Initialize real-time broker, event log and cache: Redis for multiple instances, in-memory for single node
+	var broker realtime.Broker
+	var eventLog realtime.EventLog
+	var cacheStore cache.Store
+	if redisClient != nil {
+		broker = realtime.NewRedisBroker(redisClient)
+		eventLog = realtime.NewRedisEventLog(redisClient)
+		cacheStore = cache.NewRedisStore(redisClient)
+	} else {
+		broker = realtime.NewMemoryBroker()
+		eventLog = realtime.NewMemoryEventLog()
+		cacheStore = cache.NewMemoryStore()
+	}
+	publisher := realtime.NewPublisher(broker, eventLog)
+	hub := realtime.NewHub(broker)
+	stream := realtime.NewStream(broker, eventLog)
 
-	// Initialize Gemini Client
-	geminiClient, err := gemini.NewGeminiClient(cfg.GeminiAPIKey)
+	// Initialize AI Wingman, templates are used when no language model is configured
+	prompts, err := ai.LoadPrompts(cfg.AI.PromptVersions)
 	if err != nil {
-		fmt.Printf("Warning: Failed to initialize Gemini client: %v\n", err)
-		// Don't fail, just continue without AI features
+		return nil, fmt.Errorf("failed to load AI prompts: %w", err)
+	}
+	aiConfig := ai.Config{
+		Model:       cfg.AI.Model,
+		Temperature: cfg.AI.Temperature,
+		Timeout:     cfg.AI.Timeout,
+		Prompts:     prompts,
+	}
+	var geminiWingman *ai.GeminiWingman
+	var wingman ai.Wingman
+	switch cfg.AI.Provider {
+	case config.AIProviderGemini:
+		geminiWingman, err = ai.NewGeminiWingman(cfg.GeminiAPIKey, aiConfig)
+		if err != nil {
+			fmt.Printf("Warning: Failed to initialize Gemini client, using AI templates: %v\n", err)
+			// Don't fail, just continue with template texts
+			wingman = ai.NewFakeWingman()
+		} else {
+			wingman = geminiWingman
+		}
+	case config.AIProviderOpenAI:
+		wingman = ai.NewOpenAIWingman(cfg.AI.BaseURL, cfg.AI.APIKey, aiConfig)
+	default:
+		wingman = ai.NewFakeWingman()
+	}
+	if cfg.AI.Provider != config.AIProviderFake {
+		// Cache model texts, limit spending and fall back to templates while the model is down
+		wingman = ai.NewGuardedWingman(wingman, ai.NewFakeWingman(), cacheStore, ai.GuardConfig{
+			CacheTTL:         cfg.AI.CacheTTL,
+			DailyBudget:      cfg.AI.DailyBudget,
+			UserDailyBudget:  cfg.AI.UserDailyBudget,
+			BreakerThreshold: cfg.AI.BreakerThreshold,
+			BreakerCooldown:  cfg.AI.BreakerCooldown,
+		})
 	}
 
 	// Initialize repositories
@@ -56,9 +126,45 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 	sessionRepo := postgres.NewSessionRepository(db)
 	swipeRepo := postgres.NewSwipeRepository(db)
 	matchRepo := postgres.NewMatchRepository(db)
-	// messageRepo := postgres.NewMessageRepository(db)
-	// notificationRepo := postgres.NewNotificationRepository(db)
+	messageRepo := postgres.NewMessageRepository(db)
+	notificationRepo := postgres.NewNotificationRepository(db)
 	bigFiveRepo := postgres.NewBigFiveRepository(db)
+	embeddingRepo := postgres.NewEmbeddingRepository(db)
+	unitOfWork := postgres.NewUnitOfWork(db)
+	jobRepo := postgres.NewJobRepository(db)
+
+	// Initialize VK API client
+	vkClient := vkapi.NewClientWithBaseURL(cfg.VK.APIBaseURL)
+
+	// Initialize VK push delivery (optional)
+	var pusher *notification.Pusher
+	if cfg.VK.ServiceToken != "" {
+		pusher = notification.NewPusher(
+			vkClient,
+			userRepo,
+			cfg.VK.ServiceToken,
+		)
+		pusher.Start()
+	} else {
+		fmt.Println("Warning: VK_SERVICE_TOKEN is not set, push notifications are disabled")
+	}
+
+	// Initialize background notifier
+	notifier := notification.NewNotifier(
+		notificationRepo,
+		profileRepo,
+		publisher,
+		pusher,
+	)
+	notifier.Start()
+
+	// Initialize token manager: short-lived access tokens, rotating refresh tokens
+	tokenManager := jwt.NewTokenManager(
+		cfg.JWT.AccessSecret,
+		cfg.JWT.RefreshSecret,
+		cfg.JWT.AccessExpiryMin,
+		cfg.JWT.RefreshExpiryDay,
+	)
 
 	// Initialize use cases
 	authUseCase := auth.NewVKAuthUseCase(
@@ -66,22 +172,70 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 		profileRepo,
 		sessionRepo,
 		cfg.VK.SecretKey,
-		cfg.JWT.AccessSecret,
+		tokenManager,
+		vkClient,
+		auth.SignatureOptions{
+			Enabled: cfg.VK.VerifySignature,
+			MaxAge:  cfg.VK.LaunchParamsMaxAge,
+		},
+		cacheStore,
 	)
 
+	// Initialize expired sessions cleanup
+	sessionCleaner := auth.NewSessionCleaner(sessionRepo, cfg.JWT.SessionCleanupInterval)
+	sessionCleaner.Start()
+
+	// Initialize mass liker detection, flagged users are demoted in the feed
+	massLikerDetector := swipe.NewMassLikerDetector(swipeRepo, swipe.MassLikerConfig{
+		Window:       cfg.Swipe.MassLikerWindow,
+		MinSwipes:    cfg.Swipe.MassLikerMinSwipes,
+		MinLikeRatio: cfg.Swipe.MassLikerRatio,
+		Interval:     cfg.Swipe.MassLikerInterval,
+	})
+	massLikerDetector.Start()
+
+	// Background jobs, handlers are registered by use cases before the workers start
+	jobQueue := jobs.NewQueue(jobRepo, jobs.Config{
+		Workers:      cfg.Jobs.Workers,
+		PollInterval: cfg.Jobs.PollInterval,
+		MaxAttempts:  cfg.Jobs.MaxAttempts,
+		BaseBackoff:  cfg.Jobs.BackoffBase,
+		MaxBackoff:   cfg.Jobs.BackoffMax,
+		Timeout:      cfg.Jobs.Timeout,
+	})
+
+	// Ranked feed decks are cached and dropped when ranking inputs change
+	deckCache := feed.NewDeckCache(cacheStore)
+
 	profileUseCase := profile.NewProfileUseCase(
 		profileRepo,
 		userRepo,
+		bigFiveRepo,
+		deckCache,
+		wingman,
 	)
 
 	bigFiveUseCase := bigfive.NewBigFiveUseCase(
 		bigFiveRepo,
+		profileRepo,
+		deckCache,
 	)
 
 	feedUseCase := feed.NewFeedUseCase(
 		userRepo,
 		profileRepo,
 		swipeRepo,
+		bigFiveRepo,
+		embeddingRepo,
+		feed.NewScorer(feed.Weights{
+			Personality:      cfg.Feed.PersonalityWeight,
+			Interests:        cfg.Feed.InterestsWeight,
+			Distance:         cfg.Feed.DistanceWeight,
+			Activity:         cfg.Feed.ActivityWeight,
+			Embedding:        cfg.Feed.EmbeddingWeight,
+			ActivityHalfLife: cfg.Feed.ActivityHalfLife,
+		}),
+		deckCache,
 	)
 
 	swipeUseCase := swipe.NewSwipeUseCase(
@@ -89,7 +243,42 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 		matchRepo,
 		profileRepo,
 		userRepo,
-		geminiClient,
+		bigFiveRepo,
+		unitOfWork,
+		jobQueue,
+		wingman,
+		publisher,
+		notifier,
+		feedUseCase,
+		swipe.UndoConfig{
+			Window:     cfg.Swipe.UndoWindow,
+			DailyLimit: cfg.Swipe.UndoDailyLimit,
+		},
+		cfg.Swipe.SuperLikeDailyLimit,
+		ratelimit.NewLimiter(cacheStore, "swipes", cfg.Swipe.RatePerMinute, time.Minute),
+		ratelimit.NewLimiter(cacheStore, "likes", cfg.Swipe.LikesPerDay, 24*time.Hour),
+	)
+
+	swipeUseCase.RegisterJobs(jobQueue)
+	jobQueue.Start()
+
+	matchUseCase := match.NewMatchUseCase(
+		matchRepo,
+		profileRepo,
+		userRepo,
+		messageRepo,
+		publisher,
+	)
+
+	messageUseCase := message.NewMessageUseCase(
+		messageRepo,
+		matchRepo,
+		publisher,
+		notifier,
+	)
+
+	notificationUseCase := notification.NewNotificationUseCase(
+		notificationRepo,
 	)
 
 	// Initialize handlers
@@ -98,6 +287,18 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 	bigFiveHandler := handler.NewBigFiveHandler(bigFiveUseCase)
 	feedHandler := handler.NewFeedHandler(feedUseCase)
 	swipeHandler := handler.NewSwipeHandler(swipeUseCase)
+	matchHandler := handler.NewMatchHandler(matchUseCase)
+	messageHandler := handler.NewMessageHandler(messageUseCase)
+	notificationHandler := handler.NewNotificationHandler(notificationUseCase)
+	realtimeHandler := handler.NewRealtimeHandler(hub, messageUseCase)
+	eventsHandler := handler.NewEventsHandler(stream)
+
+	// Dev auth issues sessions without VK verification, never in production
+	var devAuthHandler *handler.DevAuthHandler
+	if cfg.Server.DevAuthEnabled() {
+		devAuthHandler = handler.NewDevAuthHandler(authUseCase)
+		fmt.Println("⚠️  Dev auth is ENABLED: /auth/test and /auth/dev/login issue sessions without VK verification")
+	}
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(authUseCase)
@@ -105,10 +306,16 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 	// Initialize router
 	router := http.NewRouter(
 		authHandler,
+		devAuthHandler,
 		profileHandler,
 		bigFiveHandler,
 		feedHandler,
 		swipeHandler,
+		matchHandler,
+		messageHandler,
+		notificationHandler,
+		realtimeHandler,
+		eventsHandler,
 		authMiddleware,
 	)
 
@@ -117,18 +324,60 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 
 	// Initialize server
 	srv := server.NewServer(&cfg.Server, ginRouter)
+	// SSE streams never finish on their own and would hold the shutdown until its timeout
+	srv.RegisterOnShutdown(eventsHandler.Close)
 
 	return &Container{
-		Config: cfg,
-		DB:     db,
-		Redis:  nil,
-		Server: srv,
-		Gemini: geminiClient,
+		Config:   cfg,
+		DB:       db,
+		Redis:    redisClient,
+		Server:   srv,
+		Gemini:   geminiWingman,
+		Broker:   broker,
+		Hub:      hub,
+		Notifier: notifier,
+		Pusher:   pusher,
+		Cleaner:  sessionCleaner,
+		Detector: massLikerDetector,
+		Jobs:     jobQueue,
 	}, nil
 }
 
 // Close closes all connections
 func (c *Container) Close() error {
+	// Running jobs may still notify users and need the database
+	if c.Jobs != nil {
+		c.Jobs.Close()
+	}
+
+	// Flush pending notifications before closing connections
+	if c.Notifier != nil {
+		c.Notifier.Close()
+	}
+	if c.Pusher != nil {
+		c.Pusher.Close()
+	}
+
+	if c.Cleaner != nil {
+		c.Cleaner.Close()
+	}
+	if c.Detector != nil {
+		c.Detector.Close()
+	}
+	if c.Gemini != nil {
+		c.Gemini.Close()
+	}
+
+	// Disconnect real-time clients
+	if c.Hub != nil {
+		c.Hub.Close()
+	}
+	if c.Broker != nil {
+		if err := c.Broker.Close(); err != nil {
+			fmt.Printf("Error closing broker: %v\n", err)
+		}
+	}
+
 	// Close Redis
 	if c.Redis != nil {
 		if err := c.Redis.Close(); err != nil {
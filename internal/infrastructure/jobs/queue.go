@@ -0,0 +1,242 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+)
+
+// updateTimeout limits saving the outcome of a job, it's not tied to the job context
+// so results are saved even if the job was cancelled by shutdown
+const updateTimeout = 5 * time.Second
+
+// Handler processes the payload of a job. A returned error retries the job with backoff,
+// errors wrapped with Permanent move it to the dead letter state right away.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Config configures job workers
+type Config struct {
+	// Workers is the number of jobs processed concurrently by this instance
+	Workers int
+	// PollInterval is how often idle workers look for new jobs
+	PollInterval time.Duration
+	// MaxAttempts is the number of runs after which a failing job is moved to the dead letter state
+	MaxAttempts int
+	// Retries are delayed by BaseBackoff doubled on every attempt, up to MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Timeout limits a single run of a job, jobs running twice as long are considered abandoned and run again
+	Timeout time.Duration
+}
+
+// permanentError marks a job error that retrying won't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks the error so the job is not retried
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Queue runs background jobs stored in Postgres.
+// Jobs survive restarts: pending jobs and jobs abandoned by a stopped worker are picked up again.
+type Queue struct {
+	repo     repository.JobRepository
+	cfg      Config
+	handlers map[string]Handler
+
+	// runCtx is the parent of job contexts, cancelled when shutdown runs out of time
+	runCtx    context.Context
+	cancelRun context.CancelFunc
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// NewQueue creates a new job queue. Register handlers and call Start to run workers.
+func NewQueue(repo repository.JobRepository, cfg Config) *Queue {
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	return &Queue{
+		repo:      repo,
+		cfg:       cfg,
+		handlers:  make(map[string]Handler),
+		runCtx:    runCtx,
+		cancelRun: cancelRun,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Register sets the handler of the job kind.
+// Must be called before Start.
+func (q *Queue) Register(kind string, handler Handler) {
+	q.handlers[kind] = handler
+}
+
+// Enqueue adds a job with the JSON encoded payload. Inside UnitOfWork the job is
+// added in the same transaction, so it only runs if the transaction commits.
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	job := &domain.Job{
+		Kind:        kind,
+		Payload:     data,
+		MaxAttempts: q.cfg.MaxAttempts,
+	}
+	if err := q.repo.Enqueue(ctx, job); err != nil {
+		return fmt.Errorf("failed to enqueue %s job: %w", kind, err)
+	}
+	return nil
+}
+
+// Start runs the workers
+func (q *Queue) Start() {
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+}
+
+// Shutdown stops taking new jobs and waits for running ones to finish.
+// When ctx is done running jobs are cancelled, they are retried after the next start.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.once.Do(func() {
+		close(q.stop)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.cancelRun()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Close stops the workers waiting for running jobs
+func (q *Queue) Close() {
+	_ = q.Shutdown(context.Background())
+	q.cancelRun()
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		default:
+		}
+
+		job, err := q.claim()
+		if err != nil {
+			if !errors.Is(err, domain.ErrJobNotFound) {
+				fmt.Printf("❌ [Jobs] Failed to claim job: %v\n", err)
+			}
+
+			select {
+			case <-q.stop:
+				return
+			case <-time.After(q.cfg.PollInterval):
+			}
+			continue
+		}
+
+		q.process(job)
+	}
+}
+
+// claim takes the next job to run
+func (q *Queue) claim() (*domain.Job, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), updateTimeout)
+	defer cancel()
+
+	return q.repo.ClaimNext(ctx, time.Now().Add(-2*q.cfg.Timeout))
+}
+
+// process runs the job and saves the outcome
+func (q *Queue) process(job *domain.Job) {
+	runErr := q.run(job)
+
+	ctx, cancel := context.WithTimeout(context.Background(), updateTimeout)
+	defer cancel()
+
+	if runErr == nil {
+		if err := q.repo.Complete(ctx, job.ID); err != nil {
+			fmt.Printf("❌ [Jobs] Failed to complete job %d (%s): %v\n", job.ID, job.Kind, err)
+		}
+		return
+	}
+
+	var permanent *permanentError
+	if errors.As(runErr, &permanent) || job.Attempts >= job.MaxAttempts {
+		fmt.Printf("❌ [Jobs] Job %d (%s) failed after %d attempts, moved to dead letter: %v\n", job.ID, job.Kind, job.Attempts, runErr)
+		if err := q.repo.Bury(ctx, job.ID, runErr.Error()); err != nil {
+			fmt.Printf("❌ [Jobs] Failed to bury job %d (%s): %v\n", job.ID, job.Kind, err)
+		}
+		return
+	}
+
+	delay := q.backoff(job.Attempts)
+	fmt.Printf("⚠️  [Jobs] Job %d (%s) failed, attempt %d/%d, retry in %s: %v\n", job.ID, job.Kind, job.Attempts, job.MaxAttempts, delay, runErr)
+	if err := q.repo.Retry(ctx, job.ID, time.Now().Add(delay), runErr.Error()); err != nil {
+		fmt.Printf("❌ [Jobs] Failed to reschedule job %d (%s): %v\n", job.ID, job.Kind, err)
+	}
+}
+
+// run calls the handler of the job, a panic fails the job
+func (q *Queue) run(job *domain.Job) (err error) {
+	handler, ok := q.handlers[job.Kind]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for job kind %q", job.Kind))
+	}
+
+	ctx, cancel := context.WithTimeout(q.runCtx, q.cfg.Timeout)
+	defer cancel()
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+
+	return handler(ctx, job.Payload)
+}
+
+// backoff returns the delay before the next attempt
+func (q *Queue) backoff(attempts int) time.Duration {
+	delay := q.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= q.cfg.MaxBackoff {
+			return q.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
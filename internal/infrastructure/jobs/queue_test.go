@@ -0,0 +1,252 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+)
+
+// fakeJobRepository keeps jobs in memory and records their outcomes
+type fakeJobRepository struct {
+	mu        sync.Mutex
+	pending   []*domain.Job
+	completed []int
+	retried   map[int]time.Time
+	buried    map[int]string
+}
+
+func newFakeJobRepository(jobs ...*domain.Job) *fakeJobRepository {
+	return &fakeJobRepository{
+		pending: jobs,
+		retried: make(map[int]time.Time),
+		buried:  make(map[int]string),
+	}
+}
+
+func (r *fakeJobRepository) Enqueue(ctx context.Context, job *domain.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job.ID = len(r.pending) + 1
+	r.pending = append(r.pending, job)
+	return nil
+}
+
+func (r *fakeJobRepository) ClaimNext(ctx context.Context, staleBefore time.Time) (*domain.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) == 0 {
+		return nil, domain.ErrJobNotFound
+	}
+	job := r.pending[0]
+	r.pending = r.pending[1:]
+	job.Attempts++
+	return job, nil
+}
+
+func (r *fakeJobRepository) Complete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.completed = append(r.completed, id)
+	return nil
+}
+
+func (r *fakeJobRepository) Retry(ctx context.Context, id int, runAt time.Time, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retried[id] = runAt
+	return nil
+}
+
+func (r *fakeJobRepository) Bury(ctx context.Context, id int, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buried[id] = lastError
+	return nil
+}
+
+func testConfig() Config {
+	return Config{
+		Workers:      1,
+		PollInterval: 10 * time.Millisecond,
+		MaxAttempts:  3,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   10 * time.Second,
+		Timeout:      time.Second,
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	q := NewQueue(newFakeJobRepository(), testConfig())
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 3, want: 4 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+		{attempts: 50, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := q.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestProcess(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name        string
+		kind        string
+		attempts    int
+		handler     Handler
+		wantOutcome string
+		wantError   string
+	}{
+		{
+			name:        "success completes",
+			kind:        "test",
+			attempts:    1,
+			handler:     func(ctx context.Context, payload json.RawMessage) error { return nil },
+			wantOutcome: "completed",
+		},
+		{
+			name:        "error retries",
+			kind:        "test",
+			attempts:    1,
+			handler:     func(ctx context.Context, payload json.RawMessage) error { return errFailed },
+			wantOutcome: "retried",
+		},
+		{
+			name:        "permanent error buries",
+			kind:        "test",
+			attempts:    1,
+			handler:     func(ctx context.Context, payload json.RawMessage) error { return Permanent(errFailed) },
+			wantOutcome: "buried",
+			wantError:   "failed",
+		},
+		{
+			name:        "last attempt buries",
+			kind:        "test",
+			attempts:    3,
+			handler:     func(ctx context.Context, payload json.RawMessage) error { return errFailed },
+			wantOutcome: "buried",
+			wantError:   "failed",
+		},
+		{
+			name:        "panic is recovered and retried",
+			kind:        "test",
+			attempts:    1,
+			handler:     func(ctx context.Context, payload json.RawMessage) error { panic("boom") },
+			wantOutcome: "retried",
+		},
+		{
+			name:        "panic on last attempt buries",
+			kind:        "test",
+			attempts:    3,
+			handler:     func(ctx context.Context, payload json.RawMessage) error { panic("boom") },
+			wantOutcome: "buried",
+			wantError:   "job panicked: boom",
+		},
+		{
+			name:        "unknown kind buries",
+			kind:        "unknown",
+			attempts:    1,
+			handler:     func(ctx context.Context, payload json.RawMessage) error { return nil },
+			wantOutcome: "buried",
+			wantError:   "no handler",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeJobRepository()
+			q := NewQueue(repo, testConfig())
+			q.Register("test", tt.handler)
+
+			job := &domain.Job{ID: 1, Kind: tt.kind, Attempts: tt.attempts, MaxAttempts: 3}
+			before := time.Now()
+			q.process(job)
+
+			switch tt.wantOutcome {
+			case "completed":
+				if len(repo.completed) != 1 {
+					t.Fatalf("job not completed, retried %v, buried %v", repo.retried, repo.buried)
+				}
+			case "retried":
+				runAt, ok := repo.retried[job.ID]
+				if !ok {
+					t.Fatalf("job not retried, completed %v, buried %v", repo.completed, repo.buried)
+				}
+				if delay := runAt.Sub(before); delay < q.backoff(tt.attempts) {
+					t.Fatalf("retry in %v, want at least %v", delay, q.backoff(tt.attempts))
+				}
+			case "buried":
+				lastError, ok := repo.buried[job.ID]
+				if !ok {
+					t.Fatalf("job not buried, completed %v, retried %v", repo.completed, repo.retried)
+				}
+				if !strings.Contains(lastError, tt.wantError) {
+					t.Fatalf("buried with %q, want %q", lastError, tt.wantError)
+				}
+			}
+		})
+	}
+}
+
+func TestShutdownWaitsForRunningJobs(t *testing.T) {
+	repo := newFakeJobRepository(&domain.Job{ID: 1, Kind: "test", MaxAttempts: 3})
+	q := NewQueue(repo, testConfig())
+
+	started := make(chan struct{})
+	q.Register("test", func(ctx context.Context, payload json.RawMessage) error {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	q.Start()
+	<-started
+
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if len(repo.completed) != 1 {
+		t.Fatal("running job did not finish before Shutdown returned")
+	}
+}
+
+func TestShutdownCancelsRunningJobsWhenContextIsDone(t *testing.T) {
+	repo := newFakeJobRepository(&domain.Job{ID: 1, Kind: "test", MaxAttempts: 3})
+	q := NewQueue(repo, testConfig())
+
+	started := make(chan struct{})
+	q.Register("test", func(ctx context.Context, payload json.RawMessage) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	q.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := q.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The cancelled job runs again after the next start
+	if _, ok := repo.retried[1]; !ok {
+		t.Fatalf("cancelled job not retried, completed %v, buried %v", repo.completed, repo.buried)
+	}
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/cache"
+)
+
+// Result is the state of a limit after an attempt
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is the time until the current window ends and the limit resets
+	RetryAfter time.Duration
+
+	// key and windowEnd locate the counter for Refund, key is empty if nothing was counted
+	key       string
+	windowEnd time.Time
+}
+
+// Limiter allows up to limit attempts per key in fixed time windows.
+// Counters are kept in the shared store so the limit holds across server instances.
+type Limiter struct {
+	store  cache.Store
+	name   string
+	limit  int
+	window time.Duration
+}
+
+// NewLimiter creates a limiter, a non-positive limit disables it
+func NewLimiter(store cache.Store, name string, limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		store:  store,
+		name:   name,
+		limit:  limit,
+		window: window,
+	}
+}
+
+// Allow counts an attempt for the key and reports whether it is within the limit.
+// A nil or disabled limiter allows everything.
+func (l *Limiter) Allow(ctx context.Context, key string) (*Result, error) {
+	if l == nil || l.limit <= 0 || l.window <= 0 {
+		return &Result{Allowed: true, Limit: -1, Remaining: -1}, nil
+	}
+
+	// Windows are aligned to the epoch so every instance uses the same key
+	now := time.Now()
+	index := now.UnixNano() / int64(l.window)
+	windowEnd := time.Unix(0, (index+1)*int64(l.window))
+	retryAfter := windowEnd.Sub(now)
+
+	counterKey := fmt.Sprintf("ratelimit:%s:%s:%d", l.name, key, index)
+	count, err := l.store.Incr(ctx, counterKey, retryAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := l.limit - int(count)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &Result{
+		Allowed:    count <= int64(l.limit),
+		Limit:      l.limit,
+		Remaining:  remaining,
+		RetryAfter: retryAfter,
+		key:        counterKey,
+		windowEnd:  windowEnd,
+	}, nil
+}
+
+// Refund takes back an attempt counted by Allow, e.g. when the limited action failed.
+// Attempts of a window that has already ended are not refunded.
+func (l *Limiter) Refund(ctx context.Context, result *Result) error {
+	if l == nil || result == nil || result.key == "" {
+		return nil
+	}
+
+	ttl := time.Until(result.windowEnd)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return l.store.Update(ctx, result.key, ttl, func(value string, exists bool) (string, bool, error) {
+		if !exists {
+			return "", false, nil
+		}
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", false, fmt.Errorf("value of %s is not a counter", result.key)
+		}
+		if count <= 1 {
+			return "", false, nil
+		}
+		return strconv.FormatInt(count-1, 10), true, nil
+	})
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/cache"
+)
+
+func TestLimiterRefund(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewLimiter(cache.NewMemoryStore(), "test", 2, time.Hour)
+
+	first, err := limiter.Allow(ctx, "1")
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if _, err := limiter.Allow(ctx, "1"); err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if result, _ := limiter.Allow(ctx, "1"); result.Allowed {
+		t.Fatal("Allow() over the limit is allowed")
+	}
+
+	// Refunds take back the rejected attempt and one allowed attempt
+	if err := limiter.Refund(ctx, first); err != nil {
+		t.Fatalf("Refund() error = %v", err)
+	}
+	if err := limiter.Refund(ctx, first); err != nil {
+		t.Fatalf("Refund() error = %v", err)
+	}
+	result, err := limiter.Allow(ctx, "1")
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	if !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("Allow() after refund = allowed %t, remaining %d, want allowed with 0 remaining", result.Allowed, result.Remaining)
+	}
+
+	// Other keys are not affected
+	if result, _ := limiter.Allow(ctx, "2"); !result.Allowed || result.Remaining != 1 {
+		t.Fatalf("Allow() of another key = allowed %t, remaining %d", result.Allowed, result.Remaining)
+	}
+}
+
+func TestLimiterRefundEmpty(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewLimiter(cache.NewMemoryStore(), "test", 1, time.Hour)
+
+	result, err := limiter.Allow(ctx, "1")
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := limiter.Refund(ctx, result); err != nil {
+			t.Fatalf("Refund() error = %v", err)
+		}
+	}
+	if result, _ := limiter.Allow(ctx, "1"); !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("Allow() after extra refunds = allowed %t, remaining %d, want a single attempt", result.Allowed, result.Remaining)
+	}
+
+	// Results of disabled limiters and nil results are ignored
+	var disabled *Limiter
+	if err := disabled.Refund(ctx, result); err != nil {
+		t.Fatalf("Refund() of nil limiter error = %v", err)
+	}
+	if err := limiter.Refund(ctx, nil); err != nil {
+		t.Fatalf("Refund(nil) error = %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package realtime
+
+import (
+	"context"
+	"fmt"
+)
+
+// subscriptionBuffer is the number of events buffered per subscription
+const subscriptionBuffer = 64
+
+// Broker fans out events to every process that holds connections of a user
+type Broker interface {
+	// Publish sends the event to all subscribers of the user
+	Publish(ctx context.Context, userID int, event *Event) error
+	// Subscribe returns a channel of events for the user and a function to cancel the subscription
+	Subscribe(ctx context.Context, userID int) (<-chan *Event, func(), error)
+	Close() error
+}
+
+// Publisher is used by use cases to deliver events to users
+type Publisher struct {
+	broker Broker
+	log    EventLog
+}
+
+// NewPublisher creates a new publisher on top of the broker.
+// Non-ephemeral events are stored in the log for replay.
+func NewPublisher(broker Broker, log EventLog) *Publisher {
+	return &Publisher{
+		broker: broker,
+		log:    log,
+	}
+}
+
+// Publish delivers the event to the given users.
+// Delivery is best effort: errors are logged and never returned to the caller.
+func (p *Publisher) Publish(ctx context.Context, eventType EventType, payload interface{}, userIDs ...int) {
+	if p == nil || p.broker == nil {
+		return
+	}
+
+	event, err := NewEvent(eventType, payload)
+	if err != nil {
+		fmt.Printf("❌ [Realtime] Failed to encode %s event: %v\n", eventType, err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		// Event IDs are per user, so every recipient gets its own copy
+		userEvent := *event
+		if p.log != nil && !eventType.IsEphemeral() {
+			if err := p.log.Append(ctx, userID, &userEvent); err != nil {
+				fmt.Printf("⚠️  [Realtime] Failed to store %s event of user %d: %v\n", eventType, userID, err)
+			}
+		}
+
+		if err := p.broker.Publish(ctx, userID, &userEvent); err != nil {
+			fmt.Printf("❌ [Realtime] Failed to publish %s event to user %d: %v\n", eventType, userID, err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package realtime
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType identifies the kind of a real-time event
+type EventType string
+
+const (
+	EventNewMessage   EventType = "new_message"
+	EventMessagesRead EventType = "messages_read"
+	EventNewMatch     EventType = "new_match"
+	EventMatchRemoved EventType = "match_removed"
+	EventLikeReceived EventType = "like_received"
+	EventNotification EventType = "notification"
+	EventTyping       EventType = "typing"
+	EventPing         EventType = "ping"
+	EventPong         EventType = "pong"
+	EventError        EventType = "error"
+	EventResync       EventType = "resync"
+)
+
+// IsEphemeral reports whether the event only makes sense live
+// and must not be stored in the event log for replay
+func (t EventType) IsEphemeral() bool {
+	switch t {
+	case EventTyping, EventPing, EventPong, EventError, EventResync:
+		return true
+	}
+	return false
+}
+
+// Event is a typed JSON frame delivered to a user
+type Event struct {
+	// ID is a per-user sequence number assigned by the event log, 0 for ephemeral events
+	ID        int64           `json:"id,omitempty"`
+	Type      EventType       `json:"type"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// NewEvent creates an event with the payload encoded as JSON
+func NewEvent(eventType EventType, payload interface{}) (*Event, error) {
+	event := &Event{
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		event.Payload = data
+	}
+
+	return event, nil
+}
+
+// NewMatchPayload is sent to both users when a match is created
+type NewMatchPayload struct {
+	MatchID     int `json:"match_id"`
+	OtherUserID int `json:"other_user_id"`
+}
+
+// LikeReceivedPayload is sent to the swiped user when someone likes them
+type LikeReceivedPayload struct {
+	SwipeID     int  `json:"swipe_id"`
+	FromUserID  int  `json:"from_user_id"`
+	IsSuperLike bool `json:"is_super_like"`
+}
+
+// MatchRemovedPayload is sent to both users when a match is deactivated
+type MatchRemovedPayload struct {
+	MatchID int `json:"match_id"`
+}
+
+// MessagesReadPayload is sent to the sender when the recipient reads messages
+type MessagesReadPayload struct {
+	MatchID       int `json:"match_id"`
+	ReaderID      int `json:"reader_id"`
+	UpToMessageID int `json:"up_to_message_id"`
+}
+
+// TypingPayload is sent by the client and forwarded to the other user of the match
+type TypingPayload struct {
+	MatchID int `json:"match_id"`
+	UserID  int `json:"user_id,omitempty"`
+}
+
+// ErrorPayload is sent to the client when an inbound frame is rejected
+type ErrorPayload struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,78 @@
+package realtime
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// eventLogSize is the max number of events kept per user for replay
+const eventLogSize = 100
+
+// EventLog keeps a bounded history of events per user so that
+// reconnecting clients can replay what they missed
+type EventLog interface {
+	// Append assigns the next per-user ID to the event and stores it
+	Append(ctx context.Context, userID int, event *Event) error
+	// Since returns events with ID greater than lastID, oldest first.
+	// complete is false if some of the requested events were already evicted.
+	Since(ctx context.Context, userID int, lastID int64) (events []*Event, complete bool, err error)
+}
+
+// MemoryEventLog keeps event history in process memory
+type MemoryEventLog struct {
+	mu     sync.Mutex
+	logs   map[int][]*Event
+	lastID map[int]int64
+}
+
+// NewMemoryEventLog creates a new in-memory event log
+func NewMemoryEventLog() *MemoryEventLog {
+	return &MemoryEventLog{
+		logs:   make(map[int][]*Event),
+		lastID: make(map[int]int64),
+	}
+}
+
+func (l *MemoryEventLog) Append(ctx context.Context, userID int, event *Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lastID[userID]++
+	event.ID = l.lastID[userID]
+
+	log := append(l.logs[userID], event)
+	if len(log) > eventLogSize {
+		log = log[len(log)-eventLogSize:]
+	}
+	l.logs[userID] = log
+
+	return nil
+}
+
+func (l *MemoryEventLog) Since(ctx context.Context, userID int, lastID int64) ([]*Event, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return eventsSince(l.logs[userID], lastID, l.lastID[userID])
+}
+
+// eventsSince filters the log and checks that there is no gap after lastID.
+// A lastID ahead of the latest assigned ID means the sequence was reset
+// (restart or expired history), so the client has to resync as well.
+func eventsSince(log []*Event, lastID, latestID int64) ([]*Event, bool, error) {
+	events := make([]*Event, 0)
+	for _, event := range log {
+		if event.ID > lastID {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+
+	if lastID > latestID {
+		return events, false, nil
+	}
+
+	complete := len(events) == 0 || events[0].ID == lastID+1
+	return events, complete, nil
+}
@@ -0,0 +1,94 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+)
+
+// appendEvents adds n events to the log of the user
+func appendEvents(t *testing.T, log EventLog, userID, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		event, err := NewEvent(EventNewMessage, nil)
+		if err != nil {
+			t.Fatalf("NewEvent() error = %v", err)
+		}
+		if err := log.Append(context.Background(), userID, event); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+}
+
+// eventIDs returns IDs of the events in order
+func eventIDs(events []*Event) []int64 {
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestMemoryEventLogIsBounded(t *testing.T) {
+	log := NewMemoryEventLog()
+	appendEvents(t, log, 1, eventLogSize+20)
+
+	events, complete, err := log.Since(context.Background(), 1, 0)
+	if err != nil {
+		t.Fatalf("Since() error = %v", err)
+	}
+	if len(events) != eventLogSize {
+		t.Fatalf("Since() returned %d events, want %d", len(events), eventLogSize)
+	}
+	if events[0].ID != 21 || events[len(events)-1].ID != eventLogSize+20 {
+		t.Fatalf("Since() returned IDs %d..%d, want 21..%d", events[0].ID, events[len(events)-1].ID, eventLogSize+20)
+	}
+	if complete {
+		t.Fatal("Since() after evicted events is complete")
+	}
+}
+
+func TestMemoryEventLogSince(t *testing.T) {
+	log := NewMemoryEventLog()
+	appendEvents(t, log, 1, eventLogSize+10)
+	appendEvents(t, log, 2, 3)
+
+	tests := []struct {
+		name         string
+		userID       int
+		lastID       int64
+		wantFirst    int64
+		wantCount    int
+		wantComplete bool
+	}{
+		{name: "recent events", userID: 1, lastID: eventLogSize, wantFirst: eventLogSize + 1, wantCount: 10, wantComplete: true},
+		{name: "oldest kept event is next", userID: 1, lastID: 10, wantFirst: 11, wantCount: eventLogSize, wantComplete: true},
+		{name: "gap after evicted events", userID: 1, lastID: 5, wantFirst: 11, wantCount: eventLogSize, wantComplete: false},
+		{name: "up to date", userID: 1, lastID: eventLogSize + 10, wantComplete: true},
+		{name: "ahead of the log after reset", userID: 2, lastID: 50, wantComplete: false},
+		{name: "per-user sequence", userID: 2, lastID: 1, wantFirst: 2, wantCount: 2, wantComplete: true},
+		{name: "unknown user", userID: 3, lastID: 0, wantComplete: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, complete, err := log.Since(context.Background(), tt.userID, tt.lastID)
+			if err != nil {
+				t.Fatalf("Since() error = %v", err)
+			}
+			if len(events) != tt.wantCount {
+				t.Fatalf("Since() returned %d events, want %d", len(events), tt.wantCount)
+			}
+			if tt.wantCount > 0 && events[0].ID != tt.wantFirst {
+				t.Fatalf("Since() starts at ID %d, want %d", events[0].ID, tt.wantFirst)
+			}
+			if complete != tt.wantComplete {
+				t.Fatalf("Since() complete = %t, want %t", complete, tt.wantComplete)
+			}
+			for i := 1; i < len(events); i++ {
+				if events[i].ID != events[i-1].ID+1 {
+					t.Fatalf("Since() returned IDs out of order: %v", eventIDs(events))
+				}
+			}
+		})
+	}
+}
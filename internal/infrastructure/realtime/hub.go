@@ -0,0 +1,316 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Time allowed to write a frame to the client
+	writeWait = 10 * time.Second
+	// Time allowed to read the next pong from the client
+	pongWait = 60 * time.Second
+	// Send pings with this period, must be less than pongWait
+	pingPeriod = (pongWait * 9) / 10
+	// Maximum inbound frame size
+	maxFrameSize = 4096
+	// Number of outbound frames buffered per connection
+	sendBuffer = 64
+)
+
+// InboundHandler processes a frame sent by the client
+type InboundHandler func(ctx context.Context, userID int, payload json.RawMessage) error
+
+// Hub keeps WebSocket connections of users connected to this process
+// and forwards events received from the broker to them
+type Hub struct {
+	broker   Broker
+	handlers map[EventType]InboundHandler
+
+	mu      sync.Mutex
+	clients map[int]map[*client]struct{}
+	cancels map[int]func()
+	closed  bool
+}
+
+// client is a single WebSocket connection
+type client struct {
+	hub    *Hub
+	userID int
+	conn   *websocket.Conn
+	send   chan *Event
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewHub creates a new connection hub
+func NewHub(broker Broker) *Hub {
+	return &Hub{
+		broker:   broker,
+		handlers: make(map[EventType]InboundHandler),
+		clients:  make(map[int]map[*client]struct{}),
+		cancels:  make(map[int]func()),
+	}
+}
+
+// Handle registers a handler for inbound frames of the given type.
+// Must be called before the hub starts serving connections.
+func (h *Hub) Handle(eventType EventType, handler InboundHandler) {
+	h.handlers[eventType] = handler
+}
+
+// Serve registers the connection and blocks until it is closed
+func (h *Hub) Serve(userID int, conn *websocket.Conn) {
+	c := &client{
+		hub:    h,
+		userID: userID,
+		conn:   conn,
+		send:   make(chan *Event, sendBuffer),
+	}
+
+	if err := h.register(c); err != nil {
+		fmt.Printf("❌ [Realtime] Failed to register connection of user %d: %v\n", userID, err)
+		_ = conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscription failed"),
+			time.Now().Add(writeWait),
+		)
+		_ = conn.Close()
+		return
+	}
+
+	go c.writePump()
+	c.readPump()
+}
+
+// register adds the client and subscribes to the broker on the first connection of the user.
+// The broker round trip happens outside the hub lock so other connections are not blocked.
+func (h *Hub) register(c *client) error {
+	if h.addClient(c) {
+		return nil
+	}
+
+	events, cancel, err := h.broker.Subscribe(context.Background(), c.userID)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		cancel()
+		return fmt.Errorf("hub is closed")
+	}
+
+	// Another connection of the user subscribed while the lock was released
+	if conns, ok := h.clients[c.userID]; ok {
+		cancel()
+		conns[c] = struct{}{}
+		return nil
+	}
+
+	h.clients[c.userID] = map[*client]struct{}{c: {}}
+	h.cancels[c.userID] = cancel
+	go h.forward(c.userID, events)
+	return nil
+}
+
+// addClient adds the client if the user already has a broker subscription
+func (h *Hub) addClient(c *client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	conns, ok := h.clients[c.userID]
+	if !ok || h.closed {
+		return false
+	}
+	conns[c] = struct{}{}
+	return true
+}
+
+// unregister removes the client and drops the broker subscription after the last connection
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	conns, ok := h.clients[c.userID]
+	if !ok {
+		return
+	}
+	if _, ok := conns[c]; !ok {
+		return
+	}
+
+	delete(conns, c)
+	c.close()
+
+	if len(conns) == 0 {
+		delete(h.clients, c.userID)
+		if cancel, ok := h.cancels[c.userID]; ok {
+			cancel()
+			delete(h.cancels, c.userID)
+		}
+	}
+}
+
+// forward delivers broker events to all local connections of the user
+func (h *Hub) forward(userID int, events <-chan *Event) {
+	for event := range events {
+		for _, c := range h.connections(userID) {
+			c.enqueue(event)
+		}
+	}
+}
+
+// connections returns a snapshot of the local connections of the user
+func (h *Hub) connections(userID int) []*client {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	conns := make([]*client, 0, len(h.clients[userID]))
+	for c := range h.clients[userID] {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+// Close disconnects all clients
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for userID, conns := range h.clients {
+		for c := range conns {
+			c.close()
+		}
+		if cancel, ok := h.cancels[userID]; ok {
+			cancel()
+		}
+	}
+	h.clients = make(map[int]map[*client]struct{})
+	h.cancels = make(map[int]func())
+}
+
+// enqueue queues an outbound frame without blocking; slow clients are disconnected
+func (c *client) enqueue(event *Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	select {
+	case c.send <- event:
+	default:
+		fmt.Printf("⚠️  [Realtime] Send buffer of user %d is full, closing connection\n", c.userID)
+		c.closed = true
+		close(c.send)
+	}
+}
+
+// close stops the write pump which closes the connection
+func (c *client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
+}
+
+// readPump reads inbound frames until the connection fails
+func (c *client) readPump() {
+	defer func() {
+		c.hub.unregister(c)
+		_ = c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxFrameSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var frame Event
+		if err := c.conn.ReadJSON(&frame); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				fmt.Printf("⚠️  [Realtime] Connection of user %d closed: %v\n", c.userID, err)
+			}
+			return
+		}
+
+		// Any frame from the client proves the connection is alive
+		_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.handle(&frame)
+	}
+}
+
+// handle dispatches an inbound frame to its handler
+func (c *client) handle(frame *Event) {
+	if frame.Type == EventPing {
+		if pong, err := NewEvent(EventPong, nil); err == nil {
+			c.enqueue(pong)
+		}
+		return
+	}
+
+	handler, ok := c.hub.handlers[frame.Type]
+	if !ok {
+		c.sendError(fmt.Sprintf("unsupported frame type %q", frame.Type))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), writeWait)
+	defer cancel()
+
+	if err := handler(ctx, c.userID, frame.Payload); err != nil {
+		c.sendError(err.Error())
+	}
+}
+
+// sendError reports a rejected frame to the client
+func (c *client) sendError(message string) {
+	if event, err := NewEvent(EventError, ErrorPayload{Message: message}); err == nil {
+		c.enqueue(event)
+	}
+}
+
+// writePump writes outbound frames and pings the client periodically
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		_ = c.conn.Close()
+	}()
+
+	for {
+		select {
+		case event, ok := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				// Connection closed by the hub
+				_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			if err := c.conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
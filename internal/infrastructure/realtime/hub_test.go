@@ -0,0 +1,141 @@
+package realtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newHubServer serves the hub over WebSocket, the user ID is taken from the query
+func newHubServer(t *testing.T, hub *Hub) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := strconv.Atoi(r.URL.Query().Get("user_id"))
+		if err != nil {
+			http.Error(w, "bad user", http.StatusBadRequest)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		hub.Serve(userID, conn)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// dial connects as the user and waits until the hub registered the connection
+func dial(t *testing.T, server *httptest.Server, hub *Hub, userID int) *websocket.Conn {
+	t.Helper()
+	before := len(hub.connections(userID))
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "?user_id=" + strconv.Itoa(userID)
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	waitFor(t, func() bool { return len(hub.connections(userID)) == before+1 })
+	return conn
+}
+
+// waitFor polls the condition until it holds or a second passes
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// readEvent reads the next frame from the connection
+func readEvent(t *testing.T, conn *websocket.Conn) *Event {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	var event Event
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	return &event
+}
+
+func TestHubFansOutToAllConnectionsOfUser(t *testing.T) {
+	broker := NewMemoryBroker()
+	hub := NewHub(broker)
+	defer hub.Close()
+	server := newHubServer(t, hub)
+
+	phone := dial(t, server, hub, 1)
+	laptop := dial(t, server, hub, 1)
+	other := dial(t, server, hub, 2)
+
+	NewPublisher(broker, nil).Publish(context.Background(), EventNewMatch, NewMatchPayload{MatchID: 7, OtherUserID: 2}, 1)
+
+	for _, conn := range []*websocket.Conn{phone, laptop} {
+		if event := readEvent(t, conn); event.Type != EventNewMatch {
+			t.Fatalf("received %s, want %s", event.Type, EventNewMatch)
+		}
+	}
+
+	// The other user only gets the answer to its own ping
+	if err := other.WriteJSON(Event{Type: EventPing}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if event := readEvent(t, other); event.Type != EventPong {
+		t.Fatalf("other user received %s, want %s", event.Type, EventPong)
+	}
+}
+
+func TestHubDropsSubscriptionAfterLastConnection(t *testing.T) {
+	broker := NewMemoryBroker()
+	hub := NewHub(broker)
+	defer hub.Close()
+	server := newHubServer(t, hub)
+
+	first := dial(t, server, hub, 1)
+	second := dial(t, server, hub, 1)
+
+	subscriptions := func() int {
+		broker.mu.RLock()
+		defer broker.mu.RUnlock()
+		return len(broker.subs[1])
+	}
+	if got := subscriptions(); got != 1 {
+		t.Fatalf("user has %d broker subscriptions, want 1 shared by both connections", got)
+	}
+
+	_ = first.Close()
+	waitFor(t, func() bool { return len(hub.connections(1)) == 1 })
+	if got := subscriptions(); got != 1 {
+		t.Fatalf("user has %d broker subscriptions with one connection left, want 1", got)
+	}
+
+	_ = second.Close()
+	waitFor(t, func() bool { return subscriptions() == 0 })
+}
+
+func TestHubRejectsUnknownFrames(t *testing.T) {
+	hub := NewHub(NewMemoryBroker())
+	defer hub.Close()
+	server := newHubServer(t, hub)
+	conn := dial(t, server, hub, 1)
+
+	if err := conn.WriteJSON(Event{Type: "unknown"}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if event := readEvent(t, conn); event.Type != EventError {
+		t.Fatalf("received %s, want %s", event.Type, EventError)
+	}
+}
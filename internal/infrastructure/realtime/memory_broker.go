@@ -0,0 +1,85 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBroker delivers events within a single process.
+// Used for single-node deployments and tests.
+type MemoryBroker struct {
+	mu     sync.RWMutex
+	subs   map[int]map[chan *Event]struct{}
+	closed bool
+}
+
+// NewMemoryBroker creates a new in-memory broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		subs: make(map[int]map[chan *Event]struct{}),
+	}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, userID int, event *Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subs[userID] {
+		select {
+		case ch <- event:
+		default:
+			// Subscriber is too slow, drop the event instead of blocking the publisher
+		}
+	}
+
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, userID int) (<-chan *Event, func(), error) {
+	ch := make(chan *Event, subscriptionBuffer)
+
+	b.mu.Lock()
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[chan *Event]struct{})
+	}
+	b.subs[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			if _, ok := b.subs[userID][ch]; !ok {
+				return // already closed by Close
+			}
+			delete(b.subs[userID], ch)
+			if len(b.subs[userID]) == 0 {
+				delete(b.subs, userID)
+			}
+			close(ch)
+		})
+	}
+
+	return ch, cancel, nil
+}
+
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
+	for userID, chans := range b.subs {
+		for ch := range chans {
+			close(ch)
+		}
+		delete(b.subs, userID)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryBrokerFanOut(t *testing.T) {
+	ctx := context.Background()
+	broker := NewMemoryBroker()
+
+	first, cancelFirst, _ := broker.Subscribe(ctx, 1)
+	second, cancelSecond, _ := broker.Subscribe(ctx, 1)
+	other, cancelOther, _ := broker.Subscribe(ctx, 2)
+	defer cancelSecond()
+	defer cancelOther()
+
+	event, _ := NewEvent(EventNewMatch, nil)
+	if err := broker.Publish(ctx, 1, event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	for _, ch := range []<-chan *Event{first, second} {
+		if got := receive(t, ch); got != event {
+			t.Fatalf("received %+v, want the published event", got)
+		}
+	}
+	select {
+	case got := <-other:
+		t.Fatalf("other user received %+v", got)
+	default:
+	}
+
+	// A cancelled subscription is closed and no longer receives events
+	cancelFirst()
+	cancelFirst()
+	if _, ok := <-first; ok {
+		t.Fatal("cancelled subscription is open")
+	}
+	if err := broker.Publish(ctx, 1, event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	receive(t, second)
+}
+
+func TestMemoryBrokerDropsEventsOfSlowSubscribers(t *testing.T) {
+	ctx := context.Background()
+	broker := NewMemoryBroker()
+	ch, cancel, _ := broker.Subscribe(ctx, 1)
+	defer cancel()
+
+	event, _ := NewEvent(EventNewMessage, nil)
+	for i := 0; i < subscriptionBuffer+10; i++ {
+		if err := broker.Publish(ctx, 1, event); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if len(ch) != subscriptionBuffer {
+		t.Fatalf("buffered %d events, want %d", len(ch), subscriptionBuffer)
+	}
+}
+
+func TestMemoryBrokerClose(t *testing.T) {
+	ctx := context.Background()
+	broker := NewMemoryBroker()
+	ch, cancel, _ := broker.Subscribe(ctx, 1)
+
+	if err := broker.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("subscription is open after Close")
+	}
+	// Cancelling after Close must not close the channel twice
+	cancel()
+	if err := broker.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBroker fans out events between server instances via Redis pub/sub
+type RedisBroker struct {
+	client *redis.Client
+}
+
+// NewRedisBroker creates a new Redis pub/sub broker
+func NewRedisBroker(client *redis.Client) *RedisBroker {
+	return &RedisBroker{
+		client: client,
+	}
+}
+
+// userChannel returns the pub/sub channel of the user
+func userChannel(userID int) string {
+	return fmt.Sprintf("realtime:user:%d", userID)
+}
+
+func (b *RedisBroker) Publish(ctx context.Context, userID int, event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	return b.client.Publish(ctx, userChannel(userID), data).Err()
+}
+
+func (b *RedisBroker) Subscribe(ctx context.Context, userID int) (<-chan *Event, func(), error) {
+	pubsub := b.client.Subscribe(ctx, userChannel(userID))
+
+	// Wait for subscription confirmation so no events are lost after return
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	ch := make(chan *Event, subscriptionBuffer)
+	go func() {
+		defer close(ch)
+		for msg := range pubsub.Channel() {
+			var event Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				fmt.Printf("❌ [Realtime] Failed to decode event for user %d: %v\n", userID, err)
+				continue
+			}
+			select {
+			case ch <- &event:
+			default:
+				// Subscriber is too slow, drop the event
+			}
+		}
+	}()
+
+	cancel := func() {
+		_ = pubsub.Close()
+	}
+
+	return ch, cancel, nil
+}
+
+// Close does nothing: the Redis client is owned and closed by the container
+func (b *RedisBroker) Close() error {
+	return nil
+}
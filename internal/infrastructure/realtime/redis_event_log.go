@@ -0,0 +1,80 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// eventLogTTL is how long the event history of an inactive user is kept
+const eventLogTTL = 24 * time.Hour
+
+// RedisEventLog keeps event history in a capped Redis list per user
+type RedisEventLog struct {
+	client *redis.Client
+}
+
+// NewRedisEventLog creates a new Redis event log
+func NewRedisEventLog(client *redis.Client) *RedisEventLog {
+	return &RedisEventLog{
+		client: client,
+	}
+}
+
+func eventLogKey(userID int) string {
+	return fmt.Sprintf("realtime:log:%d", userID)
+}
+
+func eventSeqKey(userID int) string {
+	return fmt.Sprintf("realtime:seq:%d", userID)
+}
+
+func (l *RedisEventLog) Append(ctx context.Context, userID int, event *Event) error {
+	id, err := l.client.Incr(ctx, eventSeqKey(userID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to assign event id: %w", err)
+	}
+	event.ID = id
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	pipe := l.client.TxPipeline()
+	pipe.RPush(ctx, eventLogKey(userID), data)
+	pipe.LTrim(ctx, eventLogKey(userID), -eventLogSize, -1)
+	pipe.Expire(ctx, eventLogKey(userID), eventLogTTL)
+	pipe.Expire(ctx, eventSeqKey(userID), eventLogTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store event: %w", err)
+	}
+
+	return nil
+}
+
+func (l *RedisEventLog) Since(ctx context.Context, userID int, lastID int64) ([]*Event, bool, error) {
+	latestID, err := l.client.Get(ctx, eventSeqKey(userID)).Int64()
+	if err != nil && err != redis.Nil {
+		return nil, false, fmt.Errorf("failed to read event sequence: %w", err)
+	}
+
+	items, err := l.client.LRange(ctx, eventLogKey(userID), 0, -1).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read event log: %w", err)
+	}
+
+	log := make([]*Event, 0, len(items))
+	for _, item := range items {
+		var event Event
+		if err := json.Unmarshal([]byte(item), &event); err != nil {
+			continue
+		}
+		log = append(log, &event)
+	}
+
+	return eventsSince(log, lastID, latestID)
+}
@@ -0,0 +1,62 @@
+package realtime
+
+import (
+	"context"
+	"fmt"
+)
+
+// Stream opens resumable event streams for clients that can't hold
+// a WebSocket connection (Server-Sent Events)
+type Stream struct {
+	broker Broker
+	log    EventLog
+}
+
+// NewStream creates a new stream source
+func NewStream(broker Broker, log EventLog) *Stream {
+	return &Stream{
+		broker: broker,
+		log:    log,
+	}
+}
+
+// Subscription is an open event stream of a user
+type Subscription struct {
+	// Missed contains logged events after the requested ID, oldest first
+	Missed []*Event
+	// Complete is false if some missed events were evicted from the log
+	// and the client has to reload its state
+	Complete bool
+	// Live delivers new events, closed when the subscription is cancelled
+	Live   <-chan *Event
+	Cancel func()
+}
+
+// Open subscribes to live events of the user and loads events missed after lastID.
+// lastID = 0 means a fresh connection without replay.
+func (s *Stream) Open(ctx context.Context, userID int, lastID int64) (*Subscription, error) {
+	// Subscribe before reading the log so nothing published in between is lost
+	live, cancel, err := s.broker.Subscribe(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	sub := &Subscription{
+		Missed:   []*Event{},
+		Complete: true,
+		Live:     live,
+		Cancel:   cancel,
+	}
+
+	if lastID > 0 {
+		missed, complete, err := s.log.Since(ctx, userID, lastID)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to load missed events: %w", err)
+		}
+		sub.Missed = missed
+		sub.Complete = complete
+	}
+
+	return sub, nil
+}
@@ -0,0 +1,122 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// receive waits for the next event on the channel
+func receive(t *testing.T, ch <-chan *Event) *Event {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		if !ok {
+			t.Fatal("channel closed")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+	return nil
+}
+
+func TestStreamOpenReplaysMissedEvents(t *testing.T) {
+	ctx := context.Background()
+	broker := NewMemoryBroker()
+	log := NewMemoryEventLog()
+	publisher := NewPublisher(broker, log)
+	stream := NewStream(broker, log)
+
+	for i := 0; i < 5; i++ {
+		publisher.Publish(ctx, EventNewMessage, nil, 1)
+	}
+
+	tests := []struct {
+		name         string
+		lastID       int64
+		wantMissed   []int64
+		wantComplete bool
+	}{
+		{name: "fresh connection", lastID: 0, wantMissed: []int64{}, wantComplete: true},
+		{name: "after Last-Event-ID", lastID: 3, wantMissed: []int64{4, 5}, wantComplete: true},
+		{name: "up to date", lastID: 5, wantMissed: []int64{}, wantComplete: true},
+		{name: "unknown ID after restart", lastID: 42, wantMissed: []int64{}, wantComplete: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, err := stream.Open(ctx, 1, tt.lastID)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer sub.Cancel()
+
+			got := eventIDs(sub.Missed)
+			if len(got) != len(tt.wantMissed) {
+				t.Fatalf("Missed = %v, want %v", got, tt.wantMissed)
+			}
+			for i := range got {
+				if got[i] != tt.wantMissed[i] {
+					t.Fatalf("Missed = %v, want %v", got, tt.wantMissed)
+				}
+			}
+			if sub.Complete != tt.wantComplete {
+				t.Fatalf("Complete = %t, want %t", sub.Complete, tt.wantComplete)
+			}
+		})
+	}
+}
+
+func TestStreamOpenReportsEvictedEvents(t *testing.T) {
+	ctx := context.Background()
+	broker := NewMemoryBroker()
+	log := NewMemoryEventLog()
+	publisher := NewPublisher(broker, log)
+
+	for i := 0; i < eventLogSize+5; i++ {
+		publisher.Publish(ctx, EventNewMessage, nil, 1)
+	}
+
+	sub, err := NewStream(broker, log).Open(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer sub.Cancel()
+
+	if sub.Complete {
+		t.Fatal("Complete = true with events 3..5 evicted")
+	}
+	if len(sub.Missed) != eventLogSize || sub.Missed[0].ID != 6 {
+		t.Fatalf("Missed = %d events from ID %d, want %d from 6", len(sub.Missed), sub.Missed[0].ID, eventLogSize)
+	}
+}
+
+func TestStreamOpenDeliversLiveEvents(t *testing.T) {
+	ctx := context.Background()
+	broker := NewMemoryBroker()
+	log := NewMemoryEventLog()
+	publisher := NewPublisher(broker, log)
+
+	publisher.Publish(ctx, EventNewMessage, nil, 1)
+	sub, err := NewStream(broker, log).Open(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	publisher.Publish(ctx, EventNewMatch, nil, 1)
+	// Ephemeral events are delivered live but not logged
+	publisher.Publish(ctx, EventTyping, nil, 1)
+
+	if event := receive(t, sub.Live); event.Type != EventNewMatch || event.ID != 2 {
+		t.Fatalf("live event = %s with ID %d, want %s with ID 2", event.Type, event.ID, EventNewMatch)
+	}
+	if event := receive(t, sub.Live); event.Type != EventTyping || event.ID != 0 {
+		t.Fatalf("live event = %s with ID %d, want %s without ID", event.Type, event.ID, EventTyping)
+	}
+
+	sub.Cancel()
+	if _, ok := <-sub.Live; ok {
+		t.Fatal("Live is open after Cancel")
+	}
+}
@@ -41,6 +41,12 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// RegisterOnShutdown registers a function to call on Shutdown,
+// long-lived requests use it to return instead of blocking the shutdown
+func (s *Server) RegisterOnShutdown(f func()) {
+	s.httpServer.RegisterOnShutdown(f)
+}
+
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown(ctx context.Context) error {
 	fmt.Println("Shutting down server...")
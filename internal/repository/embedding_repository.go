@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"context"
+)
+
+type EmbeddingRepository interface {
+	// GetCombinedVector returns the combined interest embedding of the user, nil if it isn't computed
+	GetCombinedVector(ctx context.Context, userID int) ([]float32, error)
+}
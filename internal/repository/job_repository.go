@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+)
+
+type JobRepository interface {
+	Enqueue(ctx context.Context, job *domain.Job) error
+	// ClaimNext marks the next due job as running and counts the attempt. Jobs running since before
+	// staleBefore are claimed again, their worker is considered dead. Returns ErrJobNotFound if there is nothing to run.
+	ClaimNext(ctx context.Context, staleBefore time.Time) (*domain.Job, error)
+	// Complete deletes a finished job
+	Complete(ctx context.Context, id int) error
+	// Retry returns the job to the queue to run again at runAt
+	Retry(ctx context.Context, id int, runAt time.Time, lastError string) error
+	// Bury moves the job to the dead letter state
+	Bury(ctx context.Context, id int, lastError string) error
+}
@@ -8,10 +8,18 @@ import (
 
 type MatchRepository interface {
 	Create(ctx context.Context, match *domain.Match) error
+	// Upsert creates the match or reactivates one deactivated by undo,
+	// returns false if the users already have an active match or one of them removed it
+	Upsert(ctx context.Context, match *domain.Match) (bool, error)
 	GetByID(ctx context.Context, id int) (*domain.Match, error)
 	GetByUsers(ctx context.Context, user1ID, user2ID int) (*domain.Match, error)
+	// GetUserMatches and CountUserMatches skip matches whose other user has no profile
 	GetUserMatches(ctx context.Context, userID int, limit, offset int) ([]*domain.Match, error)
+	CountUserMatches(ctx context.Context, userID int) (int, error)
 	GetActiveMatches(ctx context.Context, userID int) ([]*domain.Match, error)
-	UpdateStatus(ctx context.Context, id int, isActive bool) error
+	// Deactivate deactivates the match and records why
+	Deactivate(ctx context.Context, id int, reason domain.MatchDeactivation) error
+	// UpdateAIFields saves the AI Wingman explanation and icebreakers of the match
+	UpdateAIFields(ctx context.Context, match *domain.Match) error
 	Delete(ctx context.Context, id int) error
 }
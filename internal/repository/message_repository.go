@@ -10,7 +10,11 @@ type MessageRepository interface {
 	Create(ctx context.Context, message *domain.Message) error
 	GetByID(ctx context.Context, id int) (*domain.Message, error)
 	GetMatchMessages(ctx context.Context, matchID int, limit, offset int) ([]*domain.Message, error)
+	GetMatchMessagesBefore(ctx context.Context, matchID, beforeID int, limit int) ([]*domain.Message, error)
 	MarkAsRead(ctx context.Context, messageID int) error
+	MarkReadUpTo(ctx context.Context, matchID, readerID, upToID int) (int, error)
 	GetUnreadCount(ctx context.Context, userID int) (int, error)
+	GetLastMessage(ctx context.Context, matchID int) (*domain.Message, error)
+	GetMatchUnreadCount(ctx context.Context, matchID, userID int) (int, error)
 	Delete(ctx context.Context, id int) error
 }
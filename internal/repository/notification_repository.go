@@ -9,9 +9,11 @@ import (
 type NotificationRepository interface {
 	Create(ctx context.Context, notification *domain.Notification) error
 	GetByID(ctx context.Context, id int) (*domain.Notification, error)
-	GetUserNotifications(ctx context.Context, userID int, limit, offset int) ([]*domain.Notification, error)
+	GetUserNotifications(ctx context.Context, userID int, unreadOnly bool, limit, offset int) ([]*domain.Notification, error)
+	CountUserNotifications(ctx context.Context, userID int, unreadOnly bool) (int, error)
+	HasUnreadForMatch(ctx context.Context, userID int, kind domain.NotificationKind, matchID int) (bool, error)
 	MarkAsRead(ctx context.Context, notificationID int) error
-	MarkAllAsRead(ctx context.Context, userID int) error
+	MarkAllAsRead(ctx context.Context, userID int) (int, error)
 	GetUnreadCount(ctx context.Context, userID int) (int, error)
 	Delete(ctx context.Context, id int) error
 }
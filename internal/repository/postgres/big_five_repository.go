@@ -27,7 +27,7 @@ func (r *bigFiveRepository) Create(ctx context.Context, result *domain.BigFiveRe
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id, created_at, updated_at
 	`
-	return r.db.QueryRowContext(
+	return conn(ctx, r.db).QueryRowContext(
 		ctx, query,
 		result.UserID, result.Openness, result.Conscientiousness,
 		result.Extraversion, result.Agreeableness, result.Neuroticism,
@@ -38,10 +38,10 @@ func (r *bigFiveRepository) Create(ctx context.Context, result *domain.BigFiveRe
 func (r *bigFiveRepository) GetByID(ctx context.Context, id int) (*domain.BigFiveResult, error) {
 	var result domain.BigFiveResult
 	query := `SELECT * FROM big_five_results WHERE id = $1`
-	err := r.db.GetContext(ctx, &result, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &result, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("big five result not found")
+			return nil, domain.ErrBigFiveNotFound
 		}
 		return nil, err
 	}
@@ -51,10 +51,10 @@ func (r *bigFiveRepository) GetByID(ctx context.Context, id int) (*domain.BigFiv
 func (r *bigFiveRepository) GetByUserID(ctx context.Context, userID int) (*domain.BigFiveResult, error) {
 	var result domain.BigFiveResult
 	query := `SELECT * FROM big_five_results WHERE user_id = $1`
-	err := r.db.GetContext(ctx, &result, query, userID)
+	err := conn(ctx, r.db).GetContext(ctx, &result, query, userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("big five result not found")
+			return nil, domain.ErrBigFiveNotFound
 		}
 		return nil, err
 	}
@@ -70,7 +70,7 @@ func (r *bigFiveRepository) Update(ctx context.Context, result *domain.BigFiveRe
 		WHERE id = $7
 		RETURNING updated_at
 	`
-	return r.db.QueryRowContext(
+	return conn(ctx, r.db).QueryRowContext(
 		ctx, query,
 		result.Openness, result.Conscientiousness, result.Extraversion,
 		result.Agreeableness, result.Neuroticism, result.CompletedAt,
@@ -80,7 +80,7 @@ func (r *bigFiveRepository) Update(ctx context.Context, result *domain.BigFiveRe
 
 func (r *bigFiveRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM big_five_results WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -89,7 +89,7 @@ func (r *bigFiveRepository) Delete(ctx context.Context, id int) error {
 		return err
 	}
 	if rows == 0 {
-		return errors.New("big five result not found")
+		return domain.ErrBigFiveNotFound
 	}
 	return nil
 }
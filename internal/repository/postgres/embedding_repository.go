@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type embeddingRepository struct {
+	db *sqlx.DB
+}
+
+func NewEmbeddingRepository(db *sqlx.DB) repository.EmbeddingRepository {
+	return &embeddingRepository{db: db}
+}
+
+func (r *embeddingRepository) GetCombinedVector(ctx context.Context, userID int) ([]float32, error) {
+	var vector []float32
+	query := `SELECT combined_vector FROM user_embeddings WHERE user_id = $1`
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(pq.Array(&vector))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return vector, nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type jobRepository struct {
+	db *sqlx.DB
+}
+
+func NewJobRepository(db *sqlx.DB) repository.JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) Enqueue(ctx context.Context, job *domain.Job) error {
+	payload := "{}"
+	if len(job.Payload) > 0 {
+		payload = string(job.Payload)
+	}
+
+	query := `
+		INSERT INTO jobs (kind, payload, max_attempts, run_at)
+		VALUES ($1, $2, $3, COALESCE($4, NOW()))
+		RETURNING id, status, run_at, created_at, updated_at
+	`
+	var runAt *time.Time
+	if !job.RunAt.IsZero() {
+		runAt = &job.RunAt
+	}
+	return conn(ctx, r.db).QueryRowContext(ctx, query, job.Kind, payload, job.MaxAttempts, runAt).
+		Scan(&job.ID, &job.Status, &job.RunAt, &job.CreatedAt, &job.UpdatedAt)
+}
+
+func (r *jobRepository) ClaimNext(ctx context.Context, staleBefore time.Time) (*domain.Job, error) {
+	// SKIP LOCKED lets workers of all instances claim different jobs without waiting for each other
+	query := `
+		UPDATE jobs
+		SET status = 'running', attempts = attempts + 1, locked_at = NOW(), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE (status = 'pending' AND run_at <= NOW())
+			   OR (status = 'running' AND locked_at < $1)
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+	var job domain.Job
+	err := conn(ctx, r.db).GetContext(ctx, &job, query, staleBefore)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrJobNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) Complete(ctx context.Context, id int) error {
+	query := `DELETE FROM jobs WHERE id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *jobRepository) Retry(ctx context.Context, id int, runAt time.Time, lastError string) error {
+	query := `
+		UPDATE jobs
+		SET status = 'pending', run_at = $2, last_error = $3, locked_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, runAt, lastError)
+	return err
+}
+
+func (r *jobRepository) Bury(ctx context.Context, id int, lastError string) error {
+	query := `
+		UPDATE jobs
+		SET status = 'dead', last_error = $2, locked_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, lastError)
+	return err
+}
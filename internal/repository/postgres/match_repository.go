@@ -8,9 +8,15 @@ import (
 	"github.com/gdugdh24/mpit2026-backend/internal/domain"
 	"github.com/gdugdh24/mpit2026-backend/internal/repository"
 	"github.com/jmoiron/sqlx"
-	"github.com/lib/pq"
 )
 
+// otherProfileExists keeps only matches whose other user ($1 is the current user)
+// still has a profile, so the match list and its total agree
+const otherProfileExists = `EXISTS (
+		SELECT 1 FROM profiles p
+		WHERE p.user_id = CASE WHEN m.user1_id = $1 THEN m.user2_id ELSE m.user1_id END
+	)`
+
 type matchRepository struct {
 	db *sqlx.DB
 }
@@ -31,7 +37,7 @@ func (r *matchRepository) Create(ctx context.Context, match *domain.Match) error
 		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at
 	`
-	err := r.db.QueryRowContext(ctx, query, user1ID, user2ID, match.IsActive, match.Explanation, pq.Array(match.Icebreakers)).
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, user1ID, user2ID, match.IsActive, match.Explanation, match.Icebreakers).
 		Scan(&match.ID, &match.CreatedAt)
 
 	match.User1ID = user1ID
@@ -39,10 +45,41 @@ func (r *matchRepository) Create(ctx context.Context, match *domain.Match) error
 	return err
 }
 
+func (r *matchRepository) Upsert(ctx context.Context, match *domain.Match) (bool, error) {
+	user1ID, user2ID := match.User1ID, match.User2ID
+	if user1ID > user2ID {
+		user1ID, user2ID = user2ID, user1ID
+	}
+
+	// Active matches and matches removed by a user are left untouched so the query returns no rows
+	query := `
+		INSERT INTO matches (user1_id, user2_id, is_active, match_explanation, icebreakers)
+		VALUES ($1, $2, true, $3, $4)
+		ON CONFLICT (user1_id, user2_id) DO UPDATE SET is_active = true, deactivation_reason = NULL
+		WHERE matches.is_active = false AND matches.deactivation_reason = $5
+		RETURNING id, created_at
+	`
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, user1ID, user2ID, match.Explanation, match.Icebreakers,
+		domain.MatchDeactivatedByUndo).
+		Scan(&match.ID, &match.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	match.User1ID = user1ID
+	match.User2ID = user2ID
+	match.IsActive = true
+	match.DeactivationReason = nil
+	return true, nil
+}
+
 func (r *matchRepository) GetByID(ctx context.Context, id int) (*domain.Match, error) {
 	var match domain.Match
 	query := `SELECT * FROM matches WHERE id = $1`
-	err := r.db.GetContext(ctx, &match, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &match, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrMatchNotFound
@@ -60,7 +97,7 @@ func (r *matchRepository) GetByUsers(ctx context.Context, user1ID, user2ID int)
 
 	var match domain.Match
 	query := `SELECT * FROM matches WHERE user1_id = $1 AND user2_id = $2`
-	err := r.db.GetContext(ctx, &match, query, user1ID, user2ID)
+	err := conn(ctx, r.db).GetContext(ctx, &match, query, user1ID, user2ID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrMatchNotFound
@@ -73,15 +110,27 @@ func (r *matchRepository) GetByUsers(ctx context.Context, user1ID, user2ID int)
 func (r *matchRepository) GetUserMatches(ctx context.Context, userID int, limit, offset int) ([]*domain.Match, error) {
 	var matches []*domain.Match
 	query := `
-		SELECT * FROM matches
-		WHERE (user1_id = $1 OR user2_id = $1)
-		ORDER BY created_at DESC
+		SELECT m.* FROM matches m
+		WHERE (m.user1_id = $1 OR m.user2_id = $1) AND m.is_active = true
+		  AND ` + otherProfileExists + `
+		ORDER BY m.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	err := r.db.SelectContext(ctx, &matches, query, userID, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &matches, query, userID, limit, offset)
 	return matches, err
 }
 
+func (r *matchRepository) CountUserMatches(ctx context.Context, userID int) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM matches m
+		WHERE (m.user1_id = $1 OR m.user2_id = $1) AND m.is_active = true
+		  AND ` + otherProfileExists + `
+	`
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, userID)
+	return count, err
+}
+
 func (r *matchRepository) GetActiveMatches(ctx context.Context, userID int) ([]*domain.Match, error) {
 	var matches []*domain.Match
 	query := `
@@ -89,13 +138,29 @@ func (r *matchRepository) GetActiveMatches(ctx context.Context, userID int) ([]*
 		WHERE (user1_id = $1 OR user2_id = $1) AND is_active = true
 		ORDER BY created_at DESC
 	`
-	err := r.db.SelectContext(ctx, &matches, query, userID)
+	err := conn(ctx, r.db).SelectContext(ctx, &matches, query, userID)
 	return matches, err
 }
 
-func (r *matchRepository) UpdateStatus(ctx context.Context, id int, isActive bool) error {
-	query := `UPDATE matches SET is_active = $1 WHERE id = $2`
-	result, err := r.db.ExecContext(ctx, query, isActive, id)
+func (r *matchRepository) Deactivate(ctx context.Context, id int, reason domain.MatchDeactivation) error {
+	query := `UPDATE matches SET is_active = false, deactivation_reason = $1 WHERE id = $2`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, reason, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrMatchNotFound
+	}
+	return nil
+}
+
+func (r *matchRepository) UpdateAIFields(ctx context.Context, match *domain.Match) error {
+	query := `UPDATE matches SET match_explanation = $1, icebreakers = $2, ai_prompt_version = $3 WHERE id = $4`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, match.Explanation, match.Icebreakers, match.PromptVersion, match.ID)
 	if err != nil {
 		return err
 	}
@@ -111,7 +176,7 @@ func (r *matchRepository) UpdateStatus(ctx context.Context, id int, isActive boo
 
 func (r *matchRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM matches WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
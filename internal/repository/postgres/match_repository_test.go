@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/repository/postgres/pgtest"
+)
+
+func TestMatchUpsertRestoresOnlyUndoneMatches(t *testing.T) {
+	db := pgtest.Open(t)
+	repo := NewMatchRepository(db)
+	ctx := context.Background()
+
+	var userIDs []int
+	err := db.Select(&userIDs, `
+		INSERT INTO users (vk_id, gender, birth_date)
+		SELECT 1000 + i, 'female', DATE '2000-01-01' FROM generate_series(1, 2) AS i
+		RETURNING id
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed users: %v", err)
+	}
+
+	upsert := func() (*domain.Match, bool) {
+		t.Helper()
+		m := &domain.Match{User1ID: userIDs[1], User2ID: userIDs[0]}
+		created, err := repo.Upsert(ctx, m)
+		if err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+		return m, created
+	}
+
+	m, created := upsert()
+	if !created {
+		t.Fatal("Upsert() of a new match = false, want true")
+	}
+	if _, created := upsert(); created {
+		t.Fatal("Upsert() of an active match = true, want false")
+	}
+
+	if err := repo.Deactivate(ctx, m.ID, domain.MatchDeactivatedByUndo); err != nil {
+		t.Fatalf("Deactivate() error = %v", err)
+	}
+	if restored, created := upsert(); !created || restored.ID != m.ID {
+		t.Fatalf("Upsert() of an undone match = %t with ID %d, want the match %d restored", created, restored.ID, m.ID)
+	}
+
+	if err := repo.Deactivate(ctx, m.ID, domain.MatchDeactivatedByUnmatch); err != nil {
+		t.Fatalf("Deactivate() error = %v", err)
+	}
+	if _, created := upsert(); created {
+		t.Fatal("Upsert() of a removed match = true, want false")
+	}
+
+	stored, err := repo.GetByID(ctx, m.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if stored.IsActive || stored.DeactivationReason == nil || *stored.DeactivationReason != domain.MatchDeactivatedByUnmatch {
+		t.Fatalf("removed match is active %t with reason %v", stored.IsActive, stored.DeactivationReason)
+	}
+}
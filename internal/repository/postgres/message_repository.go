@@ -24,7 +24,7 @@ func (r *messageRepository) Create(ctx context.Context, message *domain.Message)
 		VALUES ($1, $2, $3, $4)
 		RETURNING id, created_at
 	`
-	return r.db.QueryRowContext(
+	return conn(ctx, r.db).QueryRowContext(
 		ctx, query,
 		message.MatchID, message.SenderID, message.Content, message.IsRead,
 	).Scan(&message.ID, &message.CreatedAt)
@@ -33,7 +33,7 @@ func (r *messageRepository) Create(ctx context.Context, message *domain.Message)
 func (r *messageRepository) GetByID(ctx context.Context, id int) (*domain.Message, error) {
 	var message domain.Message
 	query := `SELECT * FROM messages WHERE id = $1`
-	err := r.db.GetContext(ctx, &message, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &message, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrMessageNotFound
@@ -51,13 +51,41 @@ func (r *messageRepository) GetMatchMessages(ctx context.Context, matchID int, l
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	err := r.db.SelectContext(ctx, &messages, query, matchID, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &messages, query, matchID, limit, offset)
+	return messages, err
+}
+
+// GetMatchMessagesBefore returns messages older than beforeID (newest first).
+// beforeID = 0 means start from the latest message.
+func (r *messageRepository) GetMatchMessagesBefore(ctx context.Context, matchID, beforeID int, limit int) ([]*domain.Message, error) {
+	var messages []*domain.Message
+	if beforeID == 0 {
+		query := `
+			SELECT * FROM messages
+			WHERE match_id = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2
+		`
+		err := conn(ctx, r.db).SelectContext(ctx, &messages, query, matchID, limit)
+		return messages, err
+	}
+
+	query := `
+		SELECT * FROM messages
+		WHERE match_id = $1
+		AND (created_at, id) < (
+			SELECT created_at, id FROM messages WHERE id = $2 AND match_id = $1
+		)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+	err := conn(ctx, r.db).SelectContext(ctx, &messages, query, matchID, beforeID, limit)
 	return messages, err
 }
 
 func (r *messageRepository) MarkAsRead(ctx context.Context, messageID int) error {
 	query := `UPDATE messages SET is_read = true WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, messageID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, messageID)
 	if err != nil {
 		return err
 	}
@@ -71,6 +99,26 @@ func (r *messageRepository) MarkAsRead(ctx context.Context, messageID int) error
 	return nil
 }
 
+// MarkReadUpTo marks all messages received by readerID in the match up to upToID as read
+func (r *messageRepository) MarkReadUpTo(ctx context.Context, matchID, readerID, upToID int) (int, error) {
+	query := `
+		UPDATE messages SET is_read = true
+		WHERE match_id = $1 AND sender_id != $2 AND is_read = false
+		AND (created_at, id) <= (
+			SELECT created_at, id FROM messages WHERE id = $3 AND match_id = $1
+		)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, matchID, readerID, upToID)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rows), nil
+}
+
 func (r *messageRepository) GetUnreadCount(ctx context.Context, userID int) (int, error) {
 	var count int
 	query := `
@@ -78,16 +126,45 @@ func (r *messageRepository) GetUnreadCount(ctx context.Context, userID int) (int
 		FROM messages m
 		JOIN matches ma ON m.match_id = ma.id
 		WHERE (ma.user1_id = $1 OR ma.user2_id = $1)
+		AND ma.is_active = true
 		AND m.sender_id != $1
 		AND m.is_read = false
 	`
-	err := r.db.GetContext(ctx, &count, query, userID)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, userID)
+	return count, err
+}
+
+func (r *messageRepository) GetLastMessage(ctx context.Context, matchID int) (*domain.Message, error) {
+	var message domain.Message
+	query := `
+		SELECT * FROM messages
+		WHERE match_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+	err := conn(ctx, r.db).GetContext(ctx, &message, query, matchID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrMessageNotFound
+		}
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (r *messageRepository) GetMatchUnreadCount(ctx context.Context, matchID, userID int) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM messages
+		WHERE match_id = $1 AND sender_id != $2 AND is_read = false
+	`
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, matchID, userID)
 	return count, err
 }
 
 func (r *messageRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM messages WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
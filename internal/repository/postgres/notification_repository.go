@@ -20,44 +20,64 @@ func NewNotificationRepository(db *sqlx.DB) repository.NotificationRepository {
 
 func (r *notificationRepository) Create(ctx context.Context, notification *domain.Notification) error {
 	query := `
-		INSERT INTO notifications (user_id, content, is_read)
-		VALUES ($1, $2, $3)
+		INSERT INTO notifications (user_id, kind, content, payload, is_read)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at
 	`
-	return r.db.QueryRowContext(
+	return conn(ctx, r.db).QueryRowContext(
 		ctx, query,
-		notification.UserID, notification.Content, notification.IsRead,
+		notification.UserID, notification.Kind, notification.Content, notification.Payload, notification.IsRead,
 	).Scan(&notification.ID, &notification.CreatedAt)
 }
 
 func (r *notificationRepository) GetByID(ctx context.Context, id int) (*domain.Notification, error) {
 	var notification domain.Notification
 	query := `SELECT * FROM notifications WHERE id = $1`
-	err := r.db.GetContext(ctx, &notification, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &notification, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("notification not found")
+			return nil, domain.ErrNotificationNotFound
 		}
 		return nil, err
 	}
 	return &notification, nil
 }
 
-func (r *notificationRepository) GetUserNotifications(ctx context.Context, userID int, limit, offset int) ([]*domain.Notification, error) {
+func (r *notificationRepository) GetUserNotifications(ctx context.Context, userID int, unreadOnly bool, limit, offset int) ([]*domain.Notification, error) {
 	var notifications []*domain.Notification
 	query := `
 		SELECT * FROM notifications
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		WHERE user_id = $1 AND (NOT $2 OR is_read = false)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
 	`
-	err := r.db.SelectContext(ctx, &notifications, query, userID, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &notifications, query, userID, unreadOnly, limit, offset)
 	return notifications, err
 }
 
+func (r *notificationRepository) CountUserNotifications(ctx context.Context, userID int, unreadOnly bool) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND (NOT $2 OR is_read = false)`
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, userID, unreadOnly)
+	return count, err
+}
+
+func (r *notificationRepository) HasUnreadForMatch(ctx context.Context, userID int, kind domain.NotificationKind, matchID int) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM notifications
+			WHERE user_id = $1 AND kind = $2 AND is_read = false
+			AND (payload->>'match_id')::int = $3
+		)
+	`
+	err := conn(ctx, r.db).GetContext(ctx, &exists, query, userID, kind, matchID)
+	return exists, err
+}
+
 func (r *notificationRepository) MarkAsRead(ctx context.Context, notificationID int) error {
 	query := `UPDATE notifications SET is_read = true WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, notificationID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, notificationID)
 	if err != nil {
 		return err
 	}
@@ -66,27 +86,34 @@ func (r *notificationRepository) MarkAsRead(ctx context.Context, notificationID
 		return err
 	}
 	if rows == 0 {
-		return errors.New("notification not found")
+		return domain.ErrNotificationNotFound
 	}
 	return nil
 }
 
-func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID int) error {
+func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID int) (int, error) {
 	query := `UPDATE notifications SET is_read = true WHERE user_id = $1 AND is_read = false`
-	_, err := r.db.ExecContext(ctx, query, userID)
-	return err
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rows), nil
 }
 
 func (r *notificationRepository) GetUnreadCount(ctx context.Context, userID int) (int, error) {
 	var count int
 	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND is_read = false`
-	err := r.db.GetContext(ctx, &count, query, userID)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, userID)
 	return count, err
 }
 
 func (r *notificationRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM notifications WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -95,7 +122,7 @@ func (r *notificationRepository) Delete(ctx context.Context, id int) error {
 		return err
 	}
 	if rows == 0 {
-		return errors.New("notification not found")
+		return domain.ErrNotificationNotFound
 	}
 	return nil
 }
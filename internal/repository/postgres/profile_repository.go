@@ -3,8 +3,11 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/domain"
 	"github.com/gdugdh24/mpit2026-backend/internal/repository"
@@ -26,12 +29,13 @@ func (r *profileRepository) Create(ctx context.Context, profile *domain.Profile)
 			user_id, display_name, bio, city, interests,
 			location_lat, location_lon, location_updated_at,
 			pref_min_age, pref_max_age, pref_max_distance_km, is_onboarding_complete,
-			pref_openness, pref_conscientiousness, pref_extraversion, pref_agreeableness, pref_neuroticism
+			pref_openness, pref_conscientiousness, pref_extraversion, pref_agreeableness, pref_neuroticism,
+			interested_in
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 		RETURNING id, created_at, updated_at
 	`
-	return r.db.QueryRowContext(
+	return conn(ctx, r.db).QueryRowContext(
 		ctx, query,
 		profile.UserID, profile.DisplayName, profile.Bio, profile.City,
 		pq.Array(profile.Interests), profile.LocationLat, profile.LocationLon,
@@ -39,13 +43,14 @@ func (r *profileRepository) Create(ctx context.Context, profile *domain.Profile)
 		profile.PrefMaxDistanceKm, profile.IsOnboardingComplete,
 		profile.PrefOpenness, profile.PrefConscientiousness, profile.PrefExtraversion,
 		profile.PrefAgreeableness, profile.PrefNeuroticism,
+		genderArray{&profile.InterestedIn},
 	).Scan(&profile.ID, &profile.CreatedAt, &profile.UpdatedAt)
 }
 
 func (r *profileRepository) GetByID(ctx context.Context, id int) (*domain.Profile, error) {
 	var profile domain.Profile
 	query := `SELECT * FROM profiles WHERE id = $1`
-	err := r.db.GetContext(ctx, &profile, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &profile, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrProfileNotFound
@@ -56,21 +61,32 @@ func (r *profileRepository) GetByID(ctx context.Context, id int) (*domain.Profil
 }
 
 func (r *profileRepository) GetByUserID(ctx context.Context, userID int) (*domain.Profile, error) {
+	return r.getByUserID(ctx, userID, false)
+}
+
+func (r *profileRepository) GetByUserIDForUpdate(ctx context.Context, userID int) (*domain.Profile, error) {
+	return r.getByUserID(ctx, userID, true)
+}
+
+func (r *profileRepository) getByUserID(ctx context.Context, userID int, forUpdate bool) (*domain.Profile, error) {
 	var profile domain.Profile
 	query := `
 		SELECT id, user_id, display_name, bio, city, interests,
 		       location_lat, location_lon, location_updated_at,
-		       pref_min_age, pref_max_age, pref_max_distance_km,
+		       pref_min_age, pref_max_age, pref_max_distance_km, interested_in,
 		       is_onboarding_complete,
 		       pref_openness, pref_conscientiousness, pref_extraversion,
 		       pref_agreeableness, pref_neuroticism,
 		       created_at, updated_at
 		FROM profiles WHERE user_id = $1
 	`
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(
 		&profile.ID, &profile.UserID, &profile.DisplayName, &profile.Bio, &profile.City, pq.Array(&profile.Interests),
 		&profile.LocationLat, &profile.LocationLon, &profile.LocationUpdatedAt,
-		&profile.PrefMinAge, &profile.PrefMaxAge, &profile.PrefMaxDistanceKm,
+		&profile.PrefMinAge, &profile.PrefMaxAge, &profile.PrefMaxDistanceKm, genderArray{&profile.InterestedIn},
 		&profile.IsOnboardingComplete,
 		&profile.PrefOpenness, &profile.PrefConscientiousness, &profile.PrefExtraversion,
 		&profile.PrefAgreeableness, &profile.PrefNeuroticism,
@@ -94,11 +110,12 @@ func (r *profileRepository) Update(ctx context.Context, profile *domain.Profile)
 		    is_onboarding_complete = $11,
 			pref_openness = $12, pref_conscientiousness = $13, pref_extraversion = $14,
 			pref_agreeableness = $15, pref_neuroticism = $16,
+			interested_in = $17,
 			updated_at = CURRENT_TIMESTAMP
-		WHERE id = $17
+		WHERE id = $18
 		RETURNING updated_at
 	`
-	return r.db.QueryRowContext(
+	return conn(ctx, r.db).QueryRowContext(
 		ctx, query,
 		profile.DisplayName, profile.Bio, profile.City, pq.Array(profile.Interests),
 		profile.LocationLat, profile.LocationLon, profile.LocationUpdatedAt,
@@ -106,13 +123,14 @@ func (r *profileRepository) Update(ctx context.Context, profile *domain.Profile)
 		profile.IsOnboardingComplete,
 		profile.PrefOpenness, profile.PrefConscientiousness, profile.PrefExtraversion,
 		profile.PrefAgreeableness, profile.PrefNeuroticism,
+		genderArray{&profile.InterestedIn},
 		profile.ID,
 	).Scan(&profile.UpdatedAt)
 }
 
 func (r *profileRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM profiles WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -132,7 +150,7 @@ func (r *profileRepository) UpdateOnboardingStatus(ctx context.Context, userID i
 		SET is_onboarding_complete = $1, updated_at = CURRENT_TIMESTAMP
 		WHERE user_id = $2
 	`
-	result, err := r.db.ExecContext(ctx, query, isComplete, userID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, isComplete, userID)
 	if err != nil {
 		return err
 	}
@@ -174,6 +192,248 @@ func (r *profileRepository) SearchProfiles(ctx context.Context, filters map[stri
 	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argCount, argCount+1)
 	args = append(args, limit, offset)
 
-	err := r.db.SelectContext(ctx, &profiles, query, args...)
+	err := conn(ctx, r.db).SelectContext(ctx, &profiles, query, args...)
 	return profiles, err
 }
+
+// InitIdealTraits seeds the "ideal partner" vector, traits that are already learned are kept
+func (r *profileRepository) InitIdealTraits(ctx context.Context, userID int, traits *domain.Traits) error {
+	query := `
+		UPDATE profiles
+		SET pref_openness = COALESCE(pref_openness, $1),
+		    pref_conscientiousness = COALESCE(pref_conscientiousness, $2),
+		    pref_extraversion = COALESCE(pref_extraversion, $3),
+		    pref_agreeableness = COALESCE(pref_agreeableness, $4),
+		    pref_neuroticism = COALESCE(pref_neuroticism, $5),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $6
+	`
+	result, err := conn(ctx, r.db).ExecContext(
+		ctx, query,
+		traits.Openness, traits.Conscientiousness, traits.Extraversion,
+		traits.Agreeableness, traits.Neuroticism,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrProfileNotFound
+	}
+	return nil
+}
+
+// distanceExpr is the haversine distance in km between a profile and the point ($lat, $lon)
+const distanceExpr = `6371 * 2 * ASIN(LEAST(1, SQRT(
+			POWER(SIN(RADIANS(p.location_lat::float8 - $%[1]d::float8) / 2), 2) +
+			COS(RADIANS($%[1]d::float8)) * COS(RADIANS(p.location_lat::float8)) *
+			POWER(SIN(RADIANS(p.location_lon::float8 - $%[2]d::float8) / 2), 2)
+		)))`
+
+// GetFeedCandidates returns profiles that can be shown in the feed in a single query:
+// users already swiped are excluded with an anti-join, age is checked against birth_date
+// and distance is filtered by a bounding box first and then by haversine distance.
+// Candidates without location are kept, they can't be checked against the distance limit.
+// Nearest candidates come first, then the recently online ones.
+func (r *profileRepository) GetFeedCandidates(ctx context.Context, filter *domain.CandidateFilter, limit, offset int) ([]*domain.Candidate, error) {
+	where := `
+		p.is_onboarding_complete = TRUE
+		AND p.user_id <> $1
+		AND NOT EXISTS (
+			SELECT 1 FROM swipes s
+			WHERE s.swiper_id = $1 AND s.swiped_id = p.user_id
+		)`
+	args := []interface{}{filter.UserID}
+	argCount := 2
+
+	if filter.CandidateID != 0 {
+		where += fmt.Sprintf(" AND p.user_id = $%d", argCount)
+		args = append(args, filter.CandidateID)
+		argCount++
+	}
+
+	if len(filter.Genders) > 0 {
+		genders := make([]string, 0, len(filter.Genders))
+		for _, g := range filter.Genders {
+			genders = append(genders, string(g))
+		}
+		where += fmt.Sprintf(" AND u.gender = ANY($%d)", argCount)
+		args = append(args, pq.Array(genders))
+		argCount++
+	}
+
+	// Preferences must match both ways: the candidate has to be interested in the feed owner
+	if filter.SeekerGender != "" {
+		where += fmt.Sprintf(" AND $%d = ANY(p.interested_in)", argCount)
+		args = append(args, string(filter.SeekerGender))
+		argCount++
+	}
+
+	if filter.City != nil && *filter.City != "" {
+		where += fmt.Sprintf(" AND p.city = $%d", argCount)
+		args = append(args, *filter.City)
+		argCount++
+	}
+
+	// Age bounds are turned into birth date bounds so the index on birth_date can be used
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if filter.MinAge != nil {
+		where += fmt.Sprintf(" AND u.birth_date <= $%d", argCount)
+		args = append(args, today.AddDate(-*filter.MinAge, 0, 0))
+		argCount++
+	}
+	if filter.MaxAge != nil {
+		where += fmt.Sprintf(" AND u.birth_date > $%d", argCount)
+		args = append(args, today.AddDate(-(*filter.MaxAge+1), 0, 0))
+		argCount++
+	}
+
+	distance := "NULL::float8"
+	distanceFilter := ""
+	if filter.Lat != nil && filter.Lon != nil {
+		distance = fmt.Sprintf(distanceExpr, argCount, argCount+1)
+		args = append(args, *filter.Lat, *filter.Lon)
+		argCount += 2
+
+		if filter.MaxDistanceKm != nil {
+			maxDistance := float64(*filter.MaxDistanceKm)
+			minLat, maxLat, minLon, maxLon := boundingBox(*filter.Lat, *filter.Lon, maxDistance)
+
+			box := fmt.Sprintf("p.location_lat BETWEEN $%d AND $%d", argCount, argCount+1)
+			args = append(args, minLat, maxLat)
+			argCount += 2
+			if minLon >= -180 && maxLon <= 180 {
+				box += fmt.Sprintf(" AND p.location_lon BETWEEN $%d AND $%d", argCount, argCount+1)
+				args = append(args, minLon, maxLon)
+				argCount += 2
+			}
+			where += fmt.Sprintf(" AND (p.location_lat IS NULL OR p.location_lon IS NULL OR (%s))", box)
+
+			distanceFilter = fmt.Sprintf("WHERE c.distance_km IS NULL OR c.distance_km <= $%d", argCount)
+			args = append(args, maxDistance)
+			argCount++
+		}
+	}
+
+	query := fmt.Sprintf(`
+		SELECT * FROM (
+			SELECT p.id, p.user_id, p.display_name, p.bio, p.city, p.interests,
+			       p.location_lat, p.location_lon, p.location_updated_at,
+			       p.pref_min_age, p.pref_max_age, p.pref_max_distance_km, p.interested_in,
+			       p.is_onboarding_complete,
+			       p.pref_openness, p.pref_conscientiousness, p.pref_extraversion,
+			       p.pref_agreeableness, p.pref_neuroticism,
+			       p.created_at, p.updated_at,
+			       u.gender, u.birth_date, u.last_online_at,
+			       u.mass_liker_flagged_at IS NOT NULL AS mass_liker,
+			       b.openness, b.conscientiousness, b.extraversion,
+			       b.agreeableness, b.neuroticism,
+			       e.combined_vector,
+			       EXISTS (
+			           SELECT 1 FROM swipes sl
+			           WHERE sl.swiper_id = p.user_id AND sl.swiped_id = $1 AND sl.kind = 'super_like'
+			       ) AS super_liked_me,
+			       %s AS distance_km
+			FROM profiles p
+			JOIN users u ON u.id = p.user_id
+			LEFT JOIN big_five_results b ON b.user_id = p.user_id
+			LEFT JOIN user_embeddings e ON e.user_id = p.user_id
+			WHERE %s
+		) c
+		%s
+		ORDER BY c.super_liked_me DESC, c.distance_km ASC NULLS LAST, c.last_online_at DESC NULLS LAST, c.id
+		LIMIT $%d OFFSET $%d
+	`, distance, where, distanceFilter, argCount, argCount+1)
+	args = append(args, limit, offset)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []*domain.Candidate
+	for rows.Next() {
+		profile := &domain.Profile{}
+		candidate := &domain.Candidate{Profile: profile}
+		var traits [5]sql.NullFloat64
+		if err := rows.Scan(
+			&profile.ID, &profile.UserID, &profile.DisplayName, &profile.Bio, &profile.City, pq.Array(&profile.Interests),
+			&profile.LocationLat, &profile.LocationLon, &profile.LocationUpdatedAt,
+			&profile.PrefMinAge, &profile.PrefMaxAge, &profile.PrefMaxDistanceKm, genderArray{&profile.InterestedIn},
+			&profile.IsOnboardingComplete,
+			&profile.PrefOpenness, &profile.PrefConscientiousness, &profile.PrefExtraversion,
+			&profile.PrefAgreeableness, &profile.PrefNeuroticism,
+			&profile.CreatedAt, &profile.UpdatedAt,
+			&candidate.Gender, &candidate.BirthDate, &candidate.LastOnlineAt,
+			&candidate.MassLiker,
+			&traits[0], &traits[1], &traits[2], &traits[3], &traits[4],
+			pq.Array(&candidate.Embedding),
+			&candidate.SuperLikedMe,
+			&candidate.DistanceKm,
+		); err != nil {
+			return nil, err
+		}
+		if traits[0].Valid {
+			candidate.Traits = &domain.Traits{
+				Openness:          traits[0].Float64,
+				Conscientiousness: traits[1].Float64,
+				Extraversion:      traits[2].Float64,
+				Agreeableness:     traits[3].Float64,
+				Neuroticism:       traits[4].Float64,
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, rows.Err()
+}
+
+// boundingBox returns the lat/lon box around the point that contains the circle of radiusKm
+func boundingBox(lat, lon, radiusKm float64) (minLat, maxLat, minLon, maxLon float64) {
+	const kmPerDegree = 111.045
+
+	deltaLat := radiusKm / kmPerDegree
+	minLat = math.Max(lat-deltaLat, -90)
+	maxLat = math.Min(lat+deltaLat, 90)
+
+	// Near the poles a circle covers all longitudes
+	cosLat := math.Cos(lat * math.Pi / 180)
+	if cosLat < 0.01 {
+		return minLat, maxLat, -180, 180
+	}
+	deltaLon := radiusKm / (kmPerDegree * cosLat)
+
+	return minLat, maxLat, lon - deltaLon, lon + deltaLon
+}
+
+// genderArray scans and writes a Postgres array of genders
+type genderArray struct {
+	genders *[]domain.Gender
+}
+
+func (a genderArray) Scan(src interface{}) error {
+	var values pq.StringArray
+	if err := values.Scan(src); err != nil {
+		return err
+	}
+
+	genders := make([]domain.Gender, 0, len(values))
+	for _, v := range values {
+		genders = append(genders, domain.Gender(v))
+	}
+	*a.genders = genders
+	return nil
+}
+
+func (a genderArray) Value() (driver.Value, error) {
+	values := make(pq.StringArray, 0, len(*a.genders))
+	for _, g := range *a.genders {
+		values = append(values, string(g))
+	}
+	return values.Value()
+}
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/repository/postgres/pgtest"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// feedBenchProfiles is the number of seeded candidate profiles
+	feedBenchProfiles = 30000
+	// feedBenchSwipes is the number of profiles the seeker already swiped
+	feedBenchSwipes = 3000
+	// feedBenchLimit matches the candidate pool size of the feed use case
+	feedBenchLimit = 200
+)
+
+// seedFeedCandidates fills the schema with users spread over western Russia
+// and returns the seeker: a male user interested in women located in Moscow
+func seedFeedCandidates(b *testing.B, db *sqlx.DB) int {
+	b.Helper()
+
+	queries := []string{
+		`INSERT INTO users (vk_id, gender, birth_date, last_online_at)
+		 SELECT 1000000 + i,
+		        CASE WHEN i % 2 = 0 THEN 'male' ELSE 'female' END,
+		        CURRENT_DATE - INTERVAL '18 years' - (i % 9000) * INTERVAL '1 day',
+		        CURRENT_TIMESTAMP - (i % 5000) * INTERVAL '1 minute'
+		 FROM generate_series(1, $1) AS i`,
+		// Every 20th profile has no location, coordinates span about 1000 km
+		`INSERT INTO profiles (user_id, display_name, city, interests, location_lat, location_lon,
+		                       pref_min_age, pref_max_age, pref_max_distance_km,
+		                       is_onboarding_complete, interested_in)
+		 SELECT u.id, 'User ' || u.id,
+		        CASE WHEN u.id % 3 = 0 THEN 'Москва' ELSE 'Санкт-Петербург' END,
+		        ARRAY['music', 'travel'],
+		        CASE WHEN u.id % 20 = 0 THEN NULL ELSE 51 + (u.id * 7919 % 1000) / 100.0 END,
+		        CASE WHEN u.id % 20 = 0 THEN NULL ELSE 30 + (u.id * 104729 % 1000) / 50.0 END,
+		        18, 40, 100,
+		        u.id % 10 <> 0,
+		        CASE WHEN u.id % 7 = 0 THEN ARRAY['male', 'female']
+		             WHEN u.gender = 'male' THEN ARRAY['female']
+		             ELSE ARRAY['male'] END::VARCHAR(10)[]
+		 FROM users u`,
+		`INSERT INTO big_five_results (user_id, openness, conscientiousness, extraversion,
+		                               agreeableness, neuroticism, completed_at)
+		 SELECT id, (id % 100) / 100.0, (id % 90) / 100.0, (id % 80) / 100.0,
+		        (id % 70) / 100.0, (id % 60) / 100.0, CURRENT_TIMESTAMP
+		 FROM users WHERE id % 2 = 0`,
+	}
+	for i, query := range queries {
+		var err error
+		if i == 0 {
+			_, err = db.Exec(query, feedBenchProfiles)
+		} else {
+			_, err = db.Exec(query)
+		}
+		if err != nil {
+			b.Fatalf("failed to seed feed candidates: %v", err)
+		}
+	}
+
+	var seekerID int
+	err := db.Get(&seekerID, `
+		UPDATE profiles p SET location_lat = 55.7558, location_lon = 37.6173
+		FROM users u
+		WHERE u.id = p.user_id AND u.id = (SELECT MIN(id) FROM users WHERE gender = 'male')
+		RETURNING p.user_id
+	`)
+	if err != nil {
+		b.Fatalf("failed to seed seeker: %v", err)
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO swipes (swiper_id, swiped_id, kind)
+		SELECT $1, id, CASE WHEN id % 2 = 0 THEN 'like' ELSE 'dislike' END
+		FROM users WHERE id <> $1 AND gender = 'female'
+		ORDER BY id LIMIT $2
+	`, seekerID, feedBenchSwipes)
+	if err != nil {
+		b.Fatalf("failed to seed swipes: %v", err)
+	}
+
+	if _, err := db.Exec(`ANALYZE`); err != nil {
+		b.Fatalf("failed to analyze: %v", err)
+	}
+	return seekerID
+}
+
+func BenchmarkGetFeedCandidates(b *testing.B) {
+	db := pgtest.Open(b)
+	seekerID := seedFeedCandidates(b, db)
+	repo := NewProfileRepository(db)
+
+	minAge, maxAge := 20, 35
+	maxDistance := 100
+	lat, lon := 55.7558, 37.6173
+	city := "Москва"
+	base := domain.CandidateFilter{
+		UserID:       seekerID,
+		Genders:      []domain.Gender{domain.GenderFemale},
+		SeekerGender: domain.GenderMale,
+		MinAge:       &minAge,
+		MaxAge:       &maxAge,
+	}
+
+	benchmarks := []struct {
+		name   string
+		filter func() *domain.CandidateFilter
+	}{
+		{
+			name: "gender_age",
+			filter: func() *domain.CandidateFilter {
+				f := base
+				return &f
+			},
+		},
+		{
+			name: "distance",
+			filter: func() *domain.CandidateFilter {
+				f := base
+				f.Lat, f.Lon, f.MaxDistanceKm = &lat, &lon, &maxDistance
+				return &f
+			},
+		},
+		{
+			name: "distance_unlimited",
+			filter: func() *domain.CandidateFilter {
+				f := base
+				f.Lat, f.Lon = &lat, &lon
+				return &f
+			},
+		},
+		{
+			name: "city",
+			filter: func() *domain.CandidateFilter {
+				f := base
+				f.City = &city
+				return &f
+			},
+		},
+	}
+
+	ctx := context.Background()
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			filter := bm.filter()
+
+			candidates, err := repo.GetFeedCandidates(ctx, filter, feedBenchLimit, 0)
+			if err != nil {
+				b.Fatalf("GetFeedCandidates() error = %v", err)
+			}
+			if len(candidates) == 0 {
+				b.Fatal("GetFeedCandidates() returned no candidates")
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetFeedCandidates(ctx, filter, feedBenchLimit, 0); err != nil {
+					b.Fatalf("GetFeedCandidates() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetFeedCandidatesEmbedding(t *testing.T) {
+	db := pgtest.Open(t)
+	ctx := context.Background()
+
+	var userIDs []int
+	err := db.Select(&userIDs, `
+		INSERT INTO users (vk_id, gender, birth_date)
+		SELECT 1000 + i, 'female', DATE '2000-01-01' FROM generate_series(1, 3) AS i
+		ORDER BY i
+		RETURNING id
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed users: %v", err)
+	}
+	_, err = db.Exec(`
+		INSERT INTO profiles (user_id, display_name, is_onboarding_complete, interested_in)
+		SELECT id, 'User ' || id, TRUE, ARRAY['female']::VARCHAR(10)[] FROM users
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed profiles: %v", err)
+	}
+	seeker, withVector, withoutVector := userIDs[0], userIDs[1], userIDs[2]
+	_, err = db.Exec(`INSERT INTO user_embeddings (user_id, combined_vector) VALUES ($1, '{1,0.5}'), ($2, '{0.25,1}')`, seeker, withVector)
+	if err != nil {
+		t.Fatalf("failed to seed embeddings: %v", err)
+	}
+
+	embeddings := NewEmbeddingRepository(db)
+	if vector, err := embeddings.GetCombinedVector(ctx, seeker); err != nil || len(vector) != 2 || vector[1] != 0.5 {
+		t.Fatalf("GetCombinedVector() = %v, %v, want [1 0.5]", vector, err)
+	}
+	if vector, err := embeddings.GetCombinedVector(ctx, withoutVector); err != nil || vector != nil {
+		t.Fatalf("GetCombinedVector() without embedding = %v, %v, want nil", vector, err)
+	}
+
+	candidates, err := NewProfileRepository(db).GetFeedCandidates(ctx, &domain.CandidateFilter{UserID: seeker}, 10, 0)
+	if err != nil {
+		t.Fatalf("GetFeedCandidates() error = %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("GetFeedCandidates() returned %d candidates, want 2", len(candidates))
+	}
+	for _, c := range candidates {
+		switch c.Profile.UserID {
+		case withVector:
+			if len(c.Embedding) != 2 || c.Embedding[0] != 0.25 {
+				t.Fatalf("Embedding = %v, want [0.25 1]", c.Embedding)
+			}
+		case withoutVector:
+			if c.Embedding != nil {
+				t.Fatalf("Embedding without a stored vector = %v, want nil", c.Embedding)
+			}
+		}
+	}
+}
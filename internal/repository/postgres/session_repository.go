@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/domain"
 	"github.com/gdugdh24/mpit2026-backend/internal/repository"
@@ -20,21 +21,61 @@ func NewSessionRepository(db *sqlx.DB) repository.SessionRepository {
 
 func (r *sessionRepository) Create(ctx context.Context, session *domain.Session) error {
 	query := `
-		INSERT INTO sessions (user_id, token, device_info, ip_address, expires_at)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at
+		INSERT INTO sessions (user_id, token, device_info, ip_address, expires_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
+		RETURNING id, created_at, last_seen_at
 	`
-	return r.db.QueryRowContext(
+	return conn(ctx, r.db).QueryRowContext(
 		ctx, query,
 		session.UserID, session.Token, session.DeviceInfo,
 		session.IPAddress, session.ExpiresAt,
-	).Scan(&session.ID, &session.CreatedAt)
+	).Scan(&session.ID, &session.CreatedAt, &session.LastSeenAt)
+}
+
+func (r *sessionRepository) GetByID(ctx context.Context, id int) (*domain.Session, error) {
+	var session domain.Session
+	query := `SELECT * FROM sessions WHERE id = $1`
+	err := conn(ctx, r.db).GetContext(ctx, &session, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	if session.IsExpired() {
+		return nil, domain.ErrSessionExpired
+	}
+	return &session, nil
+}
+
+// RotateTokens replaces token hashes of the session only if its refresh token is still
+// oldRefreshHash ("" for a new session), so a refresh token can be exchanged only once
+func (r *sessionRepository) RotateTokens(ctx context.Context, id int, oldRefreshHash, accessHash, refreshHash string, expiresAt time.Time) error {
+	query := `
+		UPDATE sessions
+		SET token = $1, refresh_token_hash = $2, expires_at = $3,
+		    refreshed_at = CASE WHEN $5 = '' THEN refreshed_at ELSE CURRENT_TIMESTAMP END,
+		    last_seen_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND COALESCE(refresh_token_hash, '') = $5
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, accessHash, refreshHash, expiresAt, id, oldRefreshHash)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrRefreshTokenReused
+	}
+	return nil
 }
 
 func (r *sessionRepository) GetByToken(ctx context.Context, token string) (*domain.Session, error) {
 	var session domain.Session
 	query := `SELECT * FROM sessions WHERE token = $1`
-	err := r.db.GetContext(ctx, &session, query, token)
+	err := conn(ctx, r.db).GetContext(ctx, &session, query, token)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrSessionNotFound
@@ -54,13 +95,19 @@ func (r *sessionRepository) GetByUserID(ctx context.Context, userID int) ([]*dom
 		WHERE user_id = $1 AND expires_at > CURRENT_TIMESTAMP
 		ORDER BY created_at DESC
 	`
-	err := r.db.SelectContext(ctx, &sessions, query, userID)
+	err := conn(ctx, r.db).SelectContext(ctx, &sessions, query, userID)
 	return sessions, err
 }
 
+func (r *sessionRepository) TouchLastSeen(ctx context.Context, id int) error {
+	query := `UPDATE sessions SET last_seen_at = CURRENT_TIMESTAMP WHERE id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	return err
+}
+
 func (r *sessionRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM sessions WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -76,7 +123,7 @@ func (r *sessionRepository) Delete(ctx context.Context, id int) error {
 
 func (r *sessionRepository) DeleteByToken(ctx context.Context, token string) error {
 	query := `DELETE FROM sessions WHERE token = $1`
-	result, err := r.db.ExecContext(ctx, query, token)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, token)
 	if err != nil {
 		return err
 	}
@@ -90,14 +137,22 @@ func (r *sessionRepository) DeleteByToken(ctx context.Context, token string) err
 	return nil
 }
 
-func (r *sessionRepository) DeleteExpired(ctx context.Context) error {
+func (r *sessionRepository) DeleteExpired(ctx context.Context) (int, error) {
 	query := `DELETE FROM sessions WHERE expires_at < CURRENT_TIMESTAMP`
-	_, err := r.db.ExecContext(ctx, query)
-	return err
+	result, err := conn(ctx, r.db).ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	return int(rows), err
 }
 
-func (r *sessionRepository) DeleteByUserID(ctx context.Context, userID int) error {
+func (r *sessionRepository) DeleteByUserID(ctx context.Context, userID int) (int, error) {
 	query := `DELETE FROM sessions WHERE user_id = $1`
-	_, err := r.db.ExecContext(ctx, query, userID)
-	return err
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	return int(rows), err
 }
@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/domain"
 	"github.com/gdugdh24/mpit2026-backend/internal/repository"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type swipeRepository struct {
@@ -20,23 +22,32 @@ func NewSwipeRepository(db *sqlx.DB) repository.SwipeRepository {
 
 func (r *swipeRepository) Create(ctx context.Context, swipe *domain.Swipe) error {
 	query := `
-		INSERT INTO swipes (swiper_id, swiped_id, is_like)
-		VALUES ($1, $2, $3)
-		RETURNING id, created_at
+		INSERT INTO swipes (swiper_id, swiped_id, kind, ideal_before)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, is_like, created_at
 	`
-	return r.db.QueryRowContext(
+	err := conn(ctx, r.db).QueryRowContext(
 		ctx, query,
-		swipe.SwiperID, swipe.SwipedID, swipe.IsLike,
-	).Scan(&swipe.ID, &swipe.CreatedAt)
+		swipe.SwiperID, swipe.SwipedID, swipe.Kind, swipe.IdealBefore,
+	).Scan(&swipe.ID, &swipe.IsLike, &swipe.CreatedAt)
+	if err != nil {
+		// A concurrent request for the same pair got there first
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Constraint == "unique_swipe" {
+			return domain.ErrSwipeAlreadyExists
+		}
+		return err
+	}
+	return nil
 }
 
 func (r *swipeRepository) GetByID(ctx context.Context, id int) (*domain.Swipe, error) {
 	var swipe domain.Swipe
 	query := `SELECT * FROM swipes WHERE id = $1`
-	err := r.db.GetContext(ctx, &swipe, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &swipe, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, domain.ErrSwipeAlreadyExists
+			return nil, domain.ErrSwipeNotFound
 		}
 		return nil, err
 	}
@@ -46,7 +57,7 @@ func (r *swipeRepository) GetByID(ctx context.Context, id int) (*domain.Swipe, e
 func (r *swipeRepository) GetByUsers(ctx context.Context, swiperID, swipedID int) (*domain.Swipe, error) {
 	var swipe domain.Swipe
 	query := `SELECT * FROM swipes WHERE swiper_id = $1 AND swiped_id = $2`
-	err := r.db.GetContext(ctx, &swipe, query, swiperID, swipedID)
+	err := conn(ctx, r.db).GetContext(ctx, &swipe, query, swiperID, swipedID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -64,22 +75,45 @@ func (r *swipeRepository) GetUserSwipes(ctx context.Context, userID int, limit,
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	err := r.db.SelectContext(ctx, &swipes, query, userID, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &swipes, query, userID, limit, offset)
 	return swipes, err
 }
 
 func (r *swipeRepository) GetLikesReceived(ctx context.Context, userID int, limit, offset int) ([]*domain.Swipe, error) {
 	var swipes []*domain.Swipe
+	// Only likes from users that match the gender preferences both ways,
+	// the same rule as for feed candidates. Super likes go first.
 	query := `
-		SELECT * FROM swipes
-		WHERE swiped_id = $1 AND is_like = true
-		ORDER BY created_at DESC
+		SELECT s.* FROM swipes s
+		JOIN users su ON su.id = s.swiper_id
+		JOIN profiles sp ON sp.user_id = s.swiper_id
+		JOIN users me ON me.id = s.swiped_id
+		JOIN profiles mp ON mp.user_id = s.swiped_id
+		WHERE s.swiped_id = $1 AND s.is_like = true
+		  AND su.gender = ANY(mp.interested_in)
+		  AND me.gender = ANY(sp.interested_in)
+		ORDER BY s.kind = 'super_like' DESC, s.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	err := r.db.SelectContext(ctx, &swipes, query, userID, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &swipes, query, userID, limit, offset)
 	return swipes, err
 }
 
+func (r *swipeRepository) LockPair(ctx context.Context, user1ID, user2ID int) error {
+	// Same key regardless of who swipes whom
+	if user1ID > user2ID {
+		user1ID, user2ID = user2ID, user1ID
+	}
+	_, err := conn(ctx, r.db).ExecContext(ctx, `SELECT pg_advisory_xact_lock($1, $2)`, user1ID, user2ID)
+	return err
+}
+
+func (r *swipeRepository) LockSwiper(ctx context.Context, userID int) error {
+	// The single key space doesn't overlap with the two-key one used by LockPair
+	_, err := conn(ctx, r.db).ExecContext(ctx, `SELECT pg_advisory_xact_lock($1::bigint)`, userID)
+	return err
+}
+
 func (r *swipeRepository) CheckMutualLike(ctx context.Context, user1ID, user2ID int) (bool, error) {
 	var count int
 	query := `
@@ -87,9 +121,102 @@ func (r *swipeRepository) CheckMutualLike(ctx context.Context, user1ID, user2ID
 		WHERE ((swiper_id = $1 AND swiped_id = $2) OR (swiper_id = $2 AND swiped_id = $1))
 		AND is_like = true
 	`
-	err := r.db.GetContext(ctx, &count, query, user1ID, user2ID)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, user1ID, user2ID)
 	if err != nil {
 		return false, err
 	}
 	return count == 2, nil
 }
+
+func (r *swipeRepository) DeleteDislikes(ctx context.Context, userID int, olderThan *time.Time) (int, error) {
+	query := `
+		DELETE FROM swipes
+		WHERE swiper_id = $1 AND is_like = false
+		AND ($2::timestamptz IS NULL OR created_at < $2)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, userID, olderThan)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	return int(rows), err
+}
+
+func (r *swipeRepository) Rewind(ctx context.Context, swipe *domain.Swipe) error {
+	// Delete and record in one statement so a swipe can't be rewound twice
+	// ideal_before is returned as deleted, preference learning may have set it after the swipe was read
+	query := `
+		WITH deleted AS (
+			DELETE FROM swipes
+			WHERE id = $1 AND swiper_id = $2
+			RETURNING swiper_id, swiped_id, is_like, ideal_before
+		), rewind AS (
+			INSERT INTO swipe_rewinds (user_id, swiped_id, was_like)
+			SELECT swiper_id, swiped_id, is_like FROM deleted
+		)
+		SELECT ideal_before FROM deleted
+	`
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, swipe.ID, swipe.SwiperID).Scan(&swipe.IdealBefore)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrSwipeNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *swipeRepository) SetIdealBefore(ctx context.Context, id int, before *domain.IdealSnapshot) error {
+	query := `UPDATE swipes SET ideal_before = $1 WHERE id = $2`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, before, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrSwipeNotFound
+	}
+	return nil
+}
+
+func (r *swipeRepository) CountRewindsSince(ctx context.Context, userID int, since time.Time) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM swipe_rewinds WHERE user_id = $1 AND created_at > $2`
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, userID, since)
+	return count, err
+}
+
+func (r *swipeRepository) CountByKindSince(ctx context.Context, swiperID int, kind domain.SwipeKind, since time.Time) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM swipes WHERE swiper_id = $1 AND kind = $2 AND created_at > $3`
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, swiperID, kind, since)
+	return count, err
+}
+
+func (r *swipeRepository) FlagMassLikers(ctx context.Context, since time.Time, minSwipes int, minLikeRatio float64) (int, error) {
+	// Only rows whose flag differs from the computed one are updated,
+	// so users that stay flagged keep the time they were first flagged
+	query := `
+		WITH stats AS (
+			SELECT swiper_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE is_like) AS likes
+			FROM swipes
+			WHERE created_at > $1
+			GROUP BY swiper_id
+		), flagged AS (
+			SELECT swiper_id FROM stats
+			WHERE total >= $2 AND likes >= total * $3::float8
+		)
+		UPDATE users
+		SET mass_liker_flagged_at = CASE WHEN id IN (SELECT swiper_id FROM flagged) THEN CURRENT_TIMESTAMP END
+		WHERE (mass_liker_flagged_at IS NULL) = (id IN (SELECT swiper_id FROM flagged))
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, since, minSwipes, minLikeRatio)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	return int(rows), err
+}
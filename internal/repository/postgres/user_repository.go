@@ -9,6 +9,7 @@ import (
 	"github.com/gdugdh24/mpit2026-backend/internal/domain"
 	"github.com/gdugdh24/mpit2026-backend/internal/repository"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type userRepository struct {
@@ -25,7 +26,7 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id, created_at, updated_at
 	`
-	return r.db.QueryRowContext(
+	return conn(ctx, r.db).QueryRowContext(
 		ctx, query,
 		user.VKID, user.VKAccessToken, user.VKTokenExpiresAt,
 		user.Gender, user.BirthDate, user.IsVerified, user.IsOnline,
@@ -35,7 +36,7 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 func (r *userRepository) GetByID(ctx context.Context, id int) (*domain.User, error) {
 	var user domain.User
 	query := `SELECT * FROM users WHERE id = $1`
-	err := r.db.GetContext(ctx, &user, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &user, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrUserNotFound
@@ -48,7 +49,7 @@ func (r *userRepository) GetByID(ctx context.Context, id int) (*domain.User, err
 func (r *userRepository) GetByVKID(ctx context.Context, vkID int) (*domain.User, error) {
 	var user domain.User
 	query := `SELECT * FROM users WHERE vk_id = $1`
-	err := r.db.GetContext(ctx, &user, query, vkID)
+	err := conn(ctx, r.db).GetContext(ctx, &user, query, vkID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrUserNotFound
@@ -67,7 +68,7 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 		WHERE id = $8
 		RETURNING updated_at
 	`
-	return r.db.QueryRowContext(
+	return conn(ctx, r.db).QueryRowContext(
 		ctx, query,
 		user.VKAccessToken, user.VKTokenExpiresAt, user.Gender,
 		user.BirthDate, user.IsVerified, user.IsOnline,
@@ -77,7 +78,7 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 
 func (r *userRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM users WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -98,7 +99,7 @@ func (r *userRepository) UpdateOnlineStatus(ctx context.Context, userID int, isO
 		SET is_online = $1, last_online_at = $2, updated_at = CURRENT_TIMESTAMP
 		WHERE id = $3
 	`
-	result, err := r.db.ExecContext(ctx, query, isOnline, now, userID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, isOnline, now, userID)
 	if err != nil {
 		return err
 	}
@@ -120,6 +121,37 @@ func (r *userRepository) GetOnlineUsers(ctx context.Context, limit, offset int)
 		ORDER BY last_online_at DESC
 		LIMIT $1 OFFSET $2
 	`
-	err := r.db.SelectContext(ctx, &users, query, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &users, query, limit, offset)
 	return users, err
 }
+
+func (r *userRepository) UpdateNotificationsEnabled(ctx context.Context, userID int, enabled bool) error {
+	query := `
+		UPDATE users
+		SET notifications_enabled = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, enabled, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// GetNotifiableVKIDs returns VK IDs of the given users who allowed push notifications
+func (r *userRepository) GetNotifiableVKIDs(ctx context.Context, userIDs []int) ([]int, error) {
+	var vkIDs []int
+	query := `
+		SELECT vk_id FROM users
+		WHERE id = ANY($1) AND notifications_enabled = true
+	`
+	err := conn(ctx, r.db).SelectContext(ctx, &vkIDs, query, pq.Array(userIDs))
+	return vkIDs, err
+}
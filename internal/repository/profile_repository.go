@@ -10,8 +10,12 @@ type ProfileRepository interface {
 	Create(ctx context.Context, profile *domain.Profile) error
 	GetByID(ctx context.Context, id int) (*domain.Profile, error)
 	GetByUserID(ctx context.Context, userID int) (*domain.Profile, error)
+	// GetByUserIDForUpdate locks the profile until the end of the transaction, must be called inside UnitOfWork
+	GetByUserIDForUpdate(ctx context.Context, userID int) (*domain.Profile, error)
 	Update(ctx context.Context, profile *domain.Profile) error
 	Delete(ctx context.Context, id int) error
 	UpdateOnboardingStatus(ctx context.Context, userID int, isComplete bool) error
+	InitIdealTraits(ctx context.Context, userID int, traits *domain.Traits) error
 	SearchProfiles(ctx context.Context, filters map[string]interface{}, limit, offset int) ([]*domain.Profile, error)
+	GetFeedCandidates(ctx context.Context, filter *domain.CandidateFilter, limit, offset int) ([]*domain.Candidate, error)
 }
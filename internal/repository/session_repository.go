@@ -2,16 +2,20 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/domain"
 )
 
 type SessionRepository interface {
 	Create(ctx context.Context, session *domain.Session) error
+	GetByID(ctx context.Context, id int) (*domain.Session, error)
 	GetByToken(ctx context.Context, token string) (*domain.Session, error)
+	RotateTokens(ctx context.Context, id int, oldRefreshHash, accessHash, refreshHash string, expiresAt time.Time) error
 	GetByUserID(ctx context.Context, userID int) ([]*domain.Session, error)
+	TouchLastSeen(ctx context.Context, id int) error
 	Delete(ctx context.Context, id int) error
 	DeleteByToken(ctx context.Context, token string) error
-	DeleteExpired(ctx context.Context) error
-	DeleteByUserID(ctx context.Context, userID int) error
+	DeleteExpired(ctx context.Context) (int, error)
+	DeleteByUserID(ctx context.Context, userID int) (int, error)
 }
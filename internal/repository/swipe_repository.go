@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/domain"
 )
@@ -12,5 +13,23 @@ type SwipeRepository interface {
 	GetByUsers(ctx context.Context, swiperID, swipedID int) (*domain.Swipe, error)
 	GetUserSwipes(ctx context.Context, userID int, limit, offset int) ([]*domain.Swipe, error)
 	GetLikesReceived(ctx context.Context, userID int, limit, offset int) ([]*domain.Swipe, error)
+	// LockPair serializes swipes between two users until the end of the transaction,
+	// must be called inside UnitOfWork
+	LockPair(ctx context.Context, user1ID, user2ID int) error
+	// LockSwiper serializes swipes of the user that check daily limits until the end of the transaction,
+	// must be called inside UnitOfWork
+	LockSwiper(ctx context.Context, userID int) error
 	CheckMutualLike(ctx context.Context, user1ID, user2ID int) (bool, error)
+	// DeleteDislikes deletes dislikes made by the user before olderThan (all if nil), returns the number deleted
+	DeleteDislikes(ctx context.Context, userID int, olderThan *time.Time) (int, error)
+	// Rewind deletes the swipe and records the rewind, returns ErrSwipeNotFound if it's already gone.
+	// swipe.IdealBefore is set to the value of the deleted row.
+	Rewind(ctx context.Context, swipe *domain.Swipe) error
+	// SetIdealBefore saves the swiper's ideal before it was learned from the swipe, returns ErrSwipeNotFound if it's gone
+	SetIdealBefore(ctx context.Context, id int, before *domain.IdealSnapshot) error
+	CountRewindsSince(ctx context.Context, userID int, since time.Time) (int, error)
+	CountByKindSince(ctx context.Context, swiperID int, kind domain.SwipeKind, since time.Time) (int, error)
+	// FlagMassLikers flags users with at least minSwipes swipes since the given time and a like ratio
+	// of at least minLikeRatio, and unflags the rest. Returns the number of users whose flag changed.
+	FlagMassLikers(ctx context.Context, since time.Time, minSwipes int, minLikeRatio float64) (int, error)
 }
@@ -14,4 +14,6 @@ type UserRepository interface {
 	Delete(ctx context.Context, id int) error
 	UpdateOnlineStatus(ctx context.Context, userID int, isOnline bool) error
 	GetOnlineUsers(ctx context.Context, limit, offset int) ([]*domain.User, error)
+	UpdateNotificationsEnabled(ctx context.Context, userID int, enabled bool) error
+	GetNotifiableVKIDs(ctx context.Context, userIDs []int) ([]int, error)
 }
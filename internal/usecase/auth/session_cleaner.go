@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+)
+
+const (
+	// DefaultSessionCleanupInterval is how often expired sessions are deleted
+	DefaultSessionCleanupInterval = time.Hour
+	// sessionCleanupTimeout limits the time spent on a single cleanup
+	sessionCleanupTimeout = time.Minute
+)
+
+// SessionCleaner periodically deletes expired sessions so the sessions table doesn't grow unbounded
+type SessionCleaner struct {
+	sessionRepo repository.SessionRepository
+	interval    time.Duration
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// NewSessionCleaner creates a new cleaner. Call Start to run it.
+func NewSessionCleaner(sessionRepo repository.SessionRepository, interval time.Duration) *SessionCleaner {
+	if interval <= 0 {
+		interval = DefaultSessionCleanupInterval
+	}
+	return &SessionCleaner{
+		sessionRepo: sessionRepo,
+		interval:    interval,
+		stop:        make(chan struct{}),
+	}
+}
+
+// Start runs the cleanup loop, the first cleanup is done immediately
+func (c *SessionCleaner) Start() {
+	c.wg.Add(1)
+	go c.run()
+}
+
+// Close stops the cleanup loop and waits for the running cleanup
+func (c *SessionCleaner) Close() {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+	c.wg.Wait()
+}
+
+func (c *SessionCleaner) run() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.cleanup()
+
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanup deletes expired sessions once
+func (c *SessionCleaner) cleanup() {
+	ctx, cancel := context.WithTimeout(context.Background(), sessionCleanupTimeout)
+	defer cancel()
+
+	count, err := c.sessionRepo.DeleteExpired(ctx)
+	if err != nil {
+		fmt.Printf("❌ [Sessions] Failed to delete expired sessions: %v\n", err)
+		return
+	}
+	if count > 0 {
+		fmt.Printf("✅ [Sessions] Deleted %d expired sessions\n", count)
+	}
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+)
+
+// lastSeenResolution limits how often last_seen_at of a session is written
+const lastSeenResolution = time.Minute
+
+// SessionInfo represents an active session (device) of the user
+type SessionInfo struct {
+	ID         int        `json:"id"`
+	DeviceInfo *string    `json:"device_info"`
+	IPAddress  *string    `json:"ip_address"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	IsCurrent  bool       `json:"is_current"`
+}
+
+// GetSessions returns active sessions of the user, currentSessionID is marked as current
+func (uc *VKAuthUseCase) GetSessions(ctx context.Context, userID, currentSessionID int) ([]*SessionInfo, error) {
+	sessions, err := uc.sessionRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	result := make([]*SessionInfo, 0, len(sessions))
+	for _, s := range sessions {
+		result = append(result, &SessionInfo{
+			ID:         s.ID,
+			DeviceInfo: s.DeviceInfo,
+			IPAddress:  s.IPAddress,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			ExpiresAt:  s.ExpiresAt,
+			IsCurrent:  s.ID == currentSessionID,
+		})
+	}
+
+	return result, nil
+}
+
+// RevokeSession deletes a session of the user, its tokens stop working immediately
+func (uc *VKAuthUseCase) RevokeSession(ctx context.Context, userID, sessionID int) error {
+	session, err := uc.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if err == domain.ErrSessionExpired {
+			return domain.ErrSessionNotFound
+		}
+		return err
+	}
+
+	// Sessions of other users are reported as missing to not reveal their IDs
+	if session.UserID != userID {
+		return domain.ErrSessionNotFound
+	}
+
+	return uc.sessionRepo.Delete(ctx, sessionID)
+}
+
+// LogoutAll deletes all sessions of the user and returns their number
+func (uc *VKAuthUseCase) LogoutAll(ctx context.Context, userID int) (int, error) {
+	count, err := uc.sessionRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	return count, nil
+}
+
+// touchSession updates last_seen_at of the session at most once per lastSeenResolution
+func (uc *VKAuthUseCase) touchSession(ctx context.Context, session *domain.Session) {
+	now := time.Now()
+	if session.SeenSince(now.Add(-lastSeenResolution)) {
+		return
+	}
+
+	if err := uc.sessionRepo.TouchLastSeen(ctx, session.ID); err != nil {
+		fmt.Printf("⚠️  Failed to update last seen of session %d: %v\n", session.ID, err)
+		return
+	}
+	session.LastSeenAt = &now
+}
@@ -7,23 +7,45 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
-	"sort"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/cache"
 	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+	"github.com/gdugdh24/mpit2026-backend/pkg/jwt"
 	"github.com/gdugdh24/mpit2026-backend/pkg/vkapi"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+const (
+	// launchParamsClockSkew is how far in the future vk_ts may be
+	launchParamsClockSkew = time.Minute
+	// defaultReplayTTL is how long used signatures are remembered if max age is not limited
+	defaultReplayTTL = time.Hour
+	// userRole is the role put into tokens of app users
+	userRole = "user"
+)
+
+// SignatureOptions configures verification of VK launch params
+type SignatureOptions struct {
+	// Enabled turns signature verification on
+	Enabled bool
+	// MaxAge rejects launch params with older vk_ts, 0 means no limit
+	MaxAge time.Duration
+}
+
 type VKAuthUseCase struct {
-	userRepo    repository.UserRepository
-	profileRepo repository.ProfileRepository
-	sessionRepo repository.SessionRepository
-	vkSecret    string
-	jwtSecret   string
-	vkAPIClient *vkapi.Client
+	userRepo         repository.UserRepository
+	profileRepo      repository.ProfileRepository
+	sessionRepo      repository.SessionRepository
+	vkSecret         string
+	tokenManager     *jwt.TokenManager
+	vkAPIClient      *vkapi.Client
+	signatureOptions SignatureOptions
+	replayStore      cache.Store
 }
 
 func NewVKAuthUseCase(
@@ -31,15 +53,20 @@ func NewVKAuthUseCase(
 	profileRepo repository.ProfileRepository,
 	sessionRepo repository.SessionRepository,
 	vkSecret string,
-	jwtSecret string,
+	tokenManager *jwt.TokenManager,
+	vkAPIClient *vkapi.Client,
+	signatureOptions SignatureOptions,
+	replayStore cache.Store,
 ) *VKAuthUseCase {
 	return &VKAuthUseCase{
-		userRepo:    userRepo,
-		profileRepo: profileRepo,
-		sessionRepo: sessionRepo,
-		vkSecret:    vkSecret,
-		jwtSecret:   jwtSecret,
-		vkAPIClient: vkapi.NewClient(),
+		userRepo:         userRepo,
+		profileRepo:      profileRepo,
+		sessionRepo:      sessionRepo,
+		vkSecret:         vkSecret,
+		tokenManager:     tokenManager,
+		vkAPIClient:      vkAPIClient,
+		signatureOptions: signatureOptions,
+		replayStore:      replayStore,
 	}
 }
 
@@ -57,20 +84,27 @@ type VKLaunchParams struct {
 
 // AuthResponse represents the authentication response
 type AuthResponse struct {
-	Token     string       `json:"token"`
-	ExpiresAt time.Time    `json:"expires_at"`
+	*TokenPair
 	User      *domain.User `json:"user"`
 	IsNewUser bool         `json:"is_new_user"`
 }
 
+// TokenPair is a short-lived access token with a rotating refresh token
+type TokenPair struct {
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
 // AuthenticateVK authenticates user via VK Mini App launch params
 func (uc *VKAuthUseCase) AuthenticateVK(ctx context.Context, params map[string]string, accessToken, deviceInfo, ipAddress string) (*AuthResponse, error) {
 	// Verify VK signature
-	// ВРЕМЕННО ОТКЛЮЧЕНО для отладки
-	fmt.Println("⚠️  WARNING: VK signature verification is DISABLED for debugging")
-	// if err := uc.verifyVKSignature(params); err != nil {
-	// 	return nil, domain.ErrInvalidVKSignature
-	// }
+	if uc.signatureOptions.Enabled {
+		if err := uc.verifyLaunchParams(ctx, params); err != nil {
+			return nil, err
+		}
+	}
 
 	vkID := 0
 	fmt.Sscanf(params["vk_user_id"], "%d", &vkID)
@@ -113,20 +147,22 @@ func (uc *VKAuthUseCase) AuthenticateVK(ctx context.Context, params map[string]s
 		}
 	}
 
+	// Launch params carry the current push notifications permission
+	uc.syncNotificationsEnabled(ctx, user, params)
+
 	// Update online status
 	if err := uc.userRepo.UpdateOnlineStatus(ctx, user.ID, true); err != nil {
 		return nil, fmt.Errorf("failed to update online status: %w", err)
 	}
 
 	// Create session
-	token, expiresAt, err := uc.createSession(ctx, user.ID, deviceInfo, ipAddress)
+	tokens, err := uc.createSession(ctx, user.ID, deviceInfo, ipAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
 	return &AuthResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
+		TokenPair: tokens,
 		User:      user,
 		IsNewUser: isNewUser,
 	}, nil
@@ -141,6 +177,10 @@ func (uc *VKAuthUseCase) AuthenticateVKTest(ctx context.Context, params map[stri
 	if vkID == 0 {
 		return nil, domain.ErrInvalidInput
 	}
+	// Real VK users can only log in with signed launch params
+	if vkID < domain.SeededVKIDMin {
+		return nil, domain.ErrForbidden
+	}
 
 	// Try to get existing user
 	user, err := uc.userRepo.GetByVKID(ctx, vkID)
@@ -157,69 +197,132 @@ func (uc *VKAuthUseCase) AuthenticateVKTest(ctx context.Context, params map[stri
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
+	// Launch params carry the current push notifications permission
+	uc.syncNotificationsEnabled(ctx, user, params)
+
 	// Update online status
 	if err := uc.userRepo.UpdateOnlineStatus(ctx, user.ID, true); err != nil {
 		return nil, fmt.Errorf("failed to update online status: %w", err)
 	}
 
 	// Create session
-	token, expiresAt, err := uc.createSession(ctx, user.ID, deviceInfo, ipAddress)
+	tokens, err := uc.createSession(ctx, user.ID, deviceInfo, ipAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
 	return &AuthResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
+		TokenPair: tokens,
 		User:      user,
 		IsNewUser: isNewUser,
 	}, nil
 }
 
-// verifyVKSignature verifies VK Mini App launch params signature
-func (uc *VKAuthUseCase) verifyVKSignature(params map[string]string) error {
-	sign := params["sign"]
-	if sign == "" {
-		fmt.Println("DEBUG: No sign parameter found")
+// syncNotificationsEnabled stores vk_are_notifications_enabled from launch params
+func (uc *VKAuthUseCase) syncNotificationsEnabled(ctx context.Context, user *domain.User, params map[string]string) {
+	enabled := params["vk_are_notifications_enabled"] == "1"
+	if user.NotificationsEnabled == enabled {
+		return
+	}
+
+	if err := uc.userRepo.UpdateNotificationsEnabled(ctx, user.ID, enabled); err != nil {
+		// Don't fail authentication, push is not critical
+		fmt.Printf("Warning: failed to update notifications permission for user %d: %v\n", user.ID, err)
+		return
+	}
+	user.NotificationsEnabled = enabled
+}
+
+// AuthenticateDev creates a session for an existing seeded user without any verification.
+// Only used by dev-auth endpoints.
+func (uc *VKAuthUseCase) AuthenticateDev(ctx context.Context, userID int, deviceInfo, ipAddress string) (*AuthResponse, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsSeeded() {
+		return nil, domain.ErrForbidden
+	}
+
+	// Update online status
+	if err := uc.userRepo.UpdateOnlineStatus(ctx, user.ID, true); err != nil {
+		return nil, fmt.Errorf("failed to update online status: %w", err)
+	}
+
+	// Create session
+	tokens, err := uc.createSession(ctx, user.ID, deviceInfo, ipAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return &AuthResponse{
+		TokenPair: tokens,
+		User:      user,
+		IsNewUser: false,
+	}, nil
+}
+
+// verifyLaunchParams checks the signature, freshness and one-time use of VK launch params
+func (uc *VKAuthUseCase) verifyLaunchParams(ctx context.Context, params map[string]string) error {
+	if err := verifyVKSignature(params, uc.vkSecret); err != nil {
+		return err
+	}
+
+	ts, err := strconv.ParseInt(params["vk_ts"], 10, 64)
+	if err != nil {
 		return domain.ErrInvalidVKSignature
 	}
+	issuedAt := time.Unix(ts, 0)
+	if issuedAt.After(time.Now().Add(launchParamsClockSkew)) {
+		return domain.ErrInvalidVKSignature
+	}
+	if uc.signatureOptions.MaxAge > 0 && time.Since(issuedAt) > uc.signatureOptions.MaxAge {
+		return domain.ErrVKLaunchParamsExpired
+	}
 
-	// Create query string from params (excluding sign and vk_access_token_settings)
-	// vk_access_token_settings is added by VK after signature generation
-	var keys []string
-	for k := range params {
-		if k != "sign" && k != "vk_access_token_settings" && strings.HasPrefix(k, "vk_") {
-			keys = append(keys, k)
+	// Signed params are valid until they expire, so remember them to block replays
+	if uc.replayStore != nil {
+		ttl := uc.signatureOptions.MaxAge
+		if ttl <= 0 {
+			ttl = defaultReplayTTL
 		}
-	}
-	sort.Strings(keys)
 
-	var queryString strings.Builder
-	for i, k := range keys {
-		if i > 0 {
-			queryString.WriteString("&")
+		ok, err := uc.replayStore.SetNX(ctx, "vk:sign:"+params["sign"], params["vk_user_id"], ttl)
+		if err != nil {
+			// Don't lock everyone out if the store is down, signature is already verified
+			fmt.Printf("⚠️  Failed to check VK launch params replay: %v\n", err)
+			return nil
+		}
+		if !ok {
+			return domain.ErrVKLaunchParamsReplayed
 		}
-		queryString.WriteString(k)
-		queryString.WriteString("=")
-		// VK doesn't use URL encoding for signature verification
-		queryString.WriteString(params[k])
 	}
 
-	fmt.Printf("DEBUG: Query string: %s\n", queryString.String())
-	fmt.Printf("DEBUG: VK Secret length: %d\n", len(uc.vkSecret))
+	return nil
+}
+
+// verifyVKSignature verifies VK Mini App launch params signature:
+// all vk_* params sorted by key and URL-encoded, HMAC-SHA256 with the app secret,
+// base64 URL-safe without padding
+func verifyVKSignature(params map[string]string, secret string) error {
+	sign := params["sign"]
+	if sign == "" {
+		return domain.ErrInvalidVKSignature
+	}
 
-	// Calculate HMAC-SHA256
-	h := hmac.New(sha256.New, []byte(uc.vkSecret))
-	h.Write([]byte(queryString.String()))
-	calculatedSign := base64.URLEncoding.EncodeToString(h.Sum(nil))
-	calculatedSign = strings.TrimRight(calculatedSign, "=")
-	calculatedSign = strings.ReplaceAll(calculatedSign, "+", "-")
-	calculatedSign = strings.ReplaceAll(calculatedSign, "/", "_")
+	query := url.Values{}
+	for k, v := range params {
+		if strings.HasPrefix(k, "vk_") {
+			query.Set(k, v)
+		}
+	}
 
-	fmt.Printf("DEBUG: Received sign: %s\n", sign)
-	fmt.Printf("DEBUG: Calculated sign: %s\n", calculatedSign)
+	// Encode sorts params by key
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(query.Encode()))
+	calculatedSign := base64.RawURLEncoding.EncodeToString(h.Sum(nil))
 
-	if sign != calculatedSign {
+	if !hmac.Equal([]byte(sign), []byte(calculatedSign)) {
 		return domain.ErrInvalidVKSignature
 	}
 
@@ -250,7 +353,32 @@ func (uc *VKAuthUseCase) createUserFromVK(ctx context.Context, vkID int, params
 		BirthDate:  birthDate,
 		IsVerified: false,
 		IsOnline:   true,
-    
+	}
+
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Auto-create profile for new user
+	displayName := params["first_name"]
+	if lastName, ok := params["last_name"]; ok && lastName != "" {
+		displayName = displayName + " " + lastName
+	}
+
+	profile := &domain.Profile{
+		UserID:       user.ID,
+		DisplayName:  displayName,
+		InterestedIn: domain.DefaultInterestedIn(user.Gender),
+	}
+
+	if err := uc.profileRepo.Create(ctx, profile); err != nil {
+		// Don't fail user creation if profile creation fails
+		fmt.Printf("Warning: failed to create profile for user %d: %v\n", user.ID, err)
+	}
+
+	return user, nil
+}
+
 // createUserFromVKInfo creates a new user from VK API data
 func (uc *VKAuthUseCase) createUserFromVKInfo(ctx context.Context, vkInfo *vkapi.VKUserInfo, accessToken string) (*domain.User, error) {
 	// Parse gender
@@ -287,14 +415,19 @@ func (uc *VKAuthUseCase) createUserFromVKInfo(ctx context.Context, vkInfo *vkapi
 	}
 
 	// Auto-create profile for new user
-	displayName := params["first_name"]
-	if lastName, ok := params["last_name"]; ok && lastName != "" {
-		displayName = displayName + " " + lastName
+	displayName := vkInfo.FirstName
+	if vkInfo.LastName != "" {
+		displayName = displayName + " " + vkInfo.LastName
 	}
 
 	profile := &domain.Profile{
-		UserID:      user.ID,
-		DisplayName: displayName,
+		UserID:       user.ID,
+		DisplayName:  displayName,
+		InterestedIn: domain.DefaultInterestedIn(user.Gender),
+	}
+	if vkInfo.City != nil && vkInfo.City.Title != "" {
+		city := vkInfo.City.Title
+		profile.City = &city
 	}
 
 	if err := uc.profileRepo.Create(ctx, profile); err != nil {
@@ -306,78 +439,145 @@ func (uc *VKAuthUseCase) createUserFromVKInfo(ctx context.Context, vkInfo *vkapi
 }
 
 // createSession creates a new session and returns JWT token
-func (uc *VKAuthUseCase) createSession(ctx context.Context, userID int, deviceInfo, ipAddress string) (string, time.Time, error) {
-	expiresAt := time.Now().Add(24 * 7 * time.Hour) // 7 days
-
-	// Generate JWT token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID,
-		"exp":     expiresAt.Unix(),
-		"iat":     time.Now().Unix(),
-	})
-
-	tokenString, err := token.SignedString([]byte(uc.jwtSecret))
-	if err != nil {
-		return "", time.Time{}, err
-	}
-
-	// Create session in DB
+func (uc *VKAuthUseCase) createSession(ctx context.Context, userID int, deviceInfo, ipAddress string) (*TokenPair, error) {
+	// Session ID goes into tokens, so the row is created first with a placeholder token
 	session := &domain.Session{
 		UserID:     userID,
-		Token:      uc.hashToken(tokenString),
+		Token:      uc.hashToken(uuid.NewString()),
 		DeviceInfo: &deviceInfo,
 		IPAddress:  &ipAddress,
-		ExpiresAt:  expiresAt,
+		ExpiresAt:  time.Now().Add(uc.tokenManager.RefreshExpiry()),
 	}
 
 	if err := uc.sessionRepo.Create(ctx, session); err != nil {
-		return "", time.Time{}, err
+		return nil, err
 	}
 
-	return tokenString, expiresAt, nil
+	return uc.issueTokens(ctx, session, "")
 }
 
-// VerifyToken verifies JWT token and returns user ID
-func (uc *VKAuthUseCase) VerifyToken(ctx context.Context, tokenString string) (int, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, domain.ErrInvalidToken
+// issueTokens generates a new token pair for the session and stores its hashes.
+// oldRefreshHash must match the stored one, otherwise the refresh token was already used.
+func (uc *VKAuthUseCase) issueTokens(ctx context.Context, session *domain.Session, oldRefreshHash string) (*TokenPair, error) {
+	accessToken, err := uc.tokenManager.GenerateAccessToken(session.UserID, session.ID, userRole)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	refreshToken, err := uc.tokenManager.GenerateRefreshToken(session.UserID, session.ID, userRole)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	now := time.Now()
+	refreshExpiresAt := now.Add(uc.tokenManager.RefreshExpiry())
+
+	err = uc.sessionRepo.RotateTokens(
+		ctx, session.ID, oldRefreshHash,
+		uc.hashToken(accessToken), uc.hashToken(refreshToken), refreshExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		Token:            accessToken,
+		ExpiresAt:        now.Add(uc.tokenManager.AccessExpiry()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair.
+// A refresh token can be used only once: presenting an already rotated token
+// means it was stolen, so the whole session is revoked.
+func (uc *VKAuthUseCase) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims, err := uc.tokenManager.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		if err == jwt.ErrExpiredToken {
+			return nil, domain.ErrSessionExpired
+		}
+		return nil, domain.ErrInvalidToken
+	}
+
+	session, err := uc.sessionRepo.GetByID(ctx, claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != claims.UserID {
+		return nil, domain.ErrInvalidToken
+	}
+
+	presentedHash := uc.hashToken(refreshToken)
+	if session.RefreshTokenHash == nil || *session.RefreshTokenHash != presentedHash {
+		uc.revokeSession(ctx, session)
+		return nil, domain.ErrRefreshTokenReused
+	}
+
+	tokens, err := uc.issueTokens(ctx, session, presentedHash)
+	if err != nil {
+		if err == domain.ErrRefreshTokenReused {
+			// Lost the race to a concurrent refresh with the same token
+			uc.revokeSession(ctx, session)
 		}
-		return []byte(uc.jwtSecret), nil
-	})
+		return nil, err
+	}
 
-	if err != nil || !token.Valid {
-		return 0, domain.ErrInvalidToken
+	return tokens, nil
+}
+
+// revokeSession deletes the session after refresh token reuse
+func (uc *VKAuthUseCase) revokeSession(ctx context.Context, session *domain.Session) {
+	fmt.Printf("⚠️  Refresh token reuse detected for session %d of user %d, revoking session\n", session.ID, session.UserID)
+	if err := uc.sessionRepo.Delete(ctx, session.ID); err != nil && err != domain.ErrSessionNotFound {
+		fmt.Printf("❌ Failed to revoke session %d: %v\n", session.ID, err)
 	}
+}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return 0, domain.ErrInvalidToken
+// VerifyToken verifies access token and returns user ID
+func (uc *VKAuthUseCase) VerifyToken(ctx context.Context, tokenString string) (int, error) {
+	session, err := uc.VerifySession(ctx, tokenString)
+	if err != nil {
+		return 0, err
 	}
+	return session.UserID, nil
+}
 
-	userID, ok := claims["user_id"].(float64)
-	if !ok {
-		return 0, domain.ErrInvalidToken
+// VerifySession verifies access token and returns its session
+func (uc *VKAuthUseCase) VerifySession(ctx context.Context, tokenString string) (*domain.Session, error) {
+	claims, err := uc.tokenManager.ValidateAccessToken(tokenString)
+	if err != nil {
+		if err == jwt.ErrExpiredToken {
+			return nil, domain.ErrTokenExpired
+		}
+		return nil, domain.ErrInvalidToken
 	}
 
-	// Verify session exists
-	hashedToken := uc.hashToken(tokenString)
-	session, err := uc.sessionRepo.GetByToken(ctx, hashedToken)
+	// Verify session still exists (not logged out or revoked)
+	session, err := uc.sessionRepo.GetByID(ctx, claims.SessionID)
 	if err != nil {
-		return 0, domain.ErrSessionNotFound
+		if err == domain.ErrSessionExpired {
+			return nil, domain.ErrSessionExpired
+		}
+		return nil, domain.ErrSessionNotFound
 	}
 
-	if session.IsExpired() {
-		return 0, domain.ErrSessionExpired
+	if session.UserID != claims.UserID {
+		return nil, domain.ErrInvalidToken
 	}
 
-	return int(userID), nil
+	uc.touchSession(ctx, session)
+
+	return session, nil
 }
 
 // Logout deletes user session
 func (uc *VKAuthUseCase) Logout(ctx context.Context, tokenString string) error {
-	hashedToken := uc.hashToken(tokenString)
-	return uc.sessionRepo.DeleteByToken(ctx, hashedToken)
+	claims, err := uc.tokenManager.ValidateAccessToken(tokenString)
+	if err != nil {
+		return domain.ErrInvalidToken
+	}
+	return uc.sessionRepo.Delete(ctx, claims.SessionID)
 }
 
 // hashToken creates SHA256 hash of token for storage
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/cache"
+)
+
+const testVKSecret = "wvl68m4dR1UpLrVRli"
+
+// knownGoodParams are launch params signed with testVKSecret by the VK algorithm
+func knownGoodParams() map[string]string {
+	return map[string]string{
+		"vk_access_token_settings":     "",
+		"vk_app_id":                    "51234567",
+		"vk_are_notifications_enabled": "0",
+		"vk_is_app_user":               "1",
+		"vk_language":                  "ru",
+		"vk_platform":                  "mobile_web",
+		"vk_ts":                        "1700000000",
+		"vk_user_id":                   "494075",
+		"sign":                         "iFMloxhu16ln_ky4THnAGMOXG7y7gXx0tC55e777OAM",
+	}
+}
+
+// signedParams returns launch params issued at the given time signed with testVKSecret
+func signedParams(issuedAt time.Time) map[string]string {
+	params := knownGoodParams()
+	delete(params, "sign")
+	params["vk_ts"] = strconv.FormatInt(issuedAt.Unix(), 10)
+
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	h := hmac.New(sha256.New, []byte(testVKSecret))
+	h.Write([]byte(query.Encode()))
+	params["sign"] = base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+	return params
+}
+
+func TestVerifyVKSignature(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  func() map[string]string
+		secret  string
+		wantErr error
+	}{
+		{
+			name:   "known good params",
+			params: knownGoodParams,
+			secret: testVKSecret,
+		},
+		{
+			name: "non vk params are not signed",
+			params: func() map[string]string {
+				p := knownGoodParams()
+				p["first_name"] = "Ivan"
+				return p
+			},
+			secret: testVKSecret,
+		},
+		{
+			name: "tampered user id",
+			params: func() map[string]string {
+				p := knownGoodParams()
+				p["vk_user_id"] = "1"
+				return p
+			},
+			secret:  testVKSecret,
+			wantErr: domain.ErrInvalidVKSignature,
+		},
+		{
+			name: "added vk param",
+			params: func() map[string]string {
+				p := knownGoodParams()
+				p["vk_ref"] = "other"
+				return p
+			},
+			secret:  testVKSecret,
+			wantErr: domain.ErrInvalidVKSignature,
+		},
+		{
+			name: "missing sign",
+			params: func() map[string]string {
+				p := knownGoodParams()
+				delete(p, "sign")
+				return p
+			},
+			secret:  testVKSecret,
+			wantErr: domain.ErrInvalidVKSignature,
+		},
+		{
+			name:    "wrong secret",
+			params:  knownGoodParams,
+			secret:  "another-secret",
+			wantErr: domain.ErrInvalidVKSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyVKSignature(tt.params(), tt.secret); err != tt.wantErr {
+				t.Fatalf("verifyVKSignature() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyLaunchParams(t *testing.T) {
+	now := time.Now()
+	maxAge := 10 * time.Minute
+
+	tests := []struct {
+		name string
+		// params are built per case so vk_ts is relative to the test start
+		params func() map[string]string
+		// usedBefore verifies the params once before the checked call
+		usedBefore bool
+		wantErr    error
+	}{
+		{
+			name:   "valid signature",
+			params: func() map[string]string { return signedParams(now) },
+		},
+		{
+			name:   "small clock skew",
+			params: func() map[string]string { return signedParams(now.Add(30 * time.Second)) },
+		},
+		{
+			name: "tampered parameter",
+			params: func() map[string]string {
+				p := signedParams(now)
+				p["vk_is_app_user"] = "0"
+				return p
+			},
+			wantErr: domain.ErrInvalidVKSignature,
+		},
+		{
+			name:    "stale vk_ts",
+			params:  func() map[string]string { return signedParams(now.Add(-maxAge - time.Minute)) },
+			wantErr: domain.ErrVKLaunchParamsExpired,
+		},
+		{
+			name:    "vk_ts from the future",
+			params:  func() map[string]string { return signedParams(now.Add(time.Hour)) },
+			wantErr: domain.ErrInvalidVKSignature,
+		},
+		{
+			name:       "replay",
+			params:     func() map[string]string { return signedParams(now.Add(-time.Minute)) },
+			usedBefore: true,
+			wantErr:    domain.ErrVKLaunchParamsReplayed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &VKAuthUseCase{
+				vkSecret: testVKSecret,
+				signatureOptions: SignatureOptions{
+					Enabled: true,
+					MaxAge:  maxAge,
+				},
+				replayStore: cache.NewMemoryStore(),
+			}
+			ctx := context.Background()
+			params := tt.params()
+
+			if tt.usedBefore {
+				if err := uc.verifyLaunchParams(ctx, params); err != nil {
+					t.Fatalf("first verifyLaunchParams() error = %v", err)
+				}
+			}
+
+			if err := uc.verifyLaunchParams(ctx, params); err != tt.wantErr {
+				t.Fatalf("verifyLaunchParams() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,27 +3,33 @@ package bigfive
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"time"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/domain"
 	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/feed"
 )
 
 type BigFiveUseCase struct {
 	bigFiveRepo repository.BigFiveRepository
+	profileRepo repository.ProfileRepository
+	decks       *feed.DeckCache
 }
 
-func NewBigFiveUseCase(bigFiveRepo repository.BigFiveRepository) *BigFiveUseCase {
+func NewBigFiveUseCase(bigFiveRepo repository.BigFiveRepository, profileRepo repository.ProfileRepository, decks *feed.DeckCache) *BigFiveUseCase {
 	return &BigFiveUseCase{
 		bigFiveRepo: bigFiveRepo,
+		profileRepo: profileRepo,
+		decks:       decks,
 	}
 }
 
 // TIPIQuestion represents a TIPI test question
 type TIPIQuestion struct {
-	ID   int               `json:"id"`
-	Text string            `json:"text"`
+	ID     int                `json:"id"`
+	Text   string             `json:"text"`
 	Traits map[string]float64 `json:"-"`
 }
 
@@ -99,6 +105,14 @@ func (uc *BigFiveUseCase) SubmitAnswers(ctx context.Context, userID int, req *TI
 		return nil, err
 	}
 
+	// Until likes teach us otherwise, the ideal partner is assumed to be similar to the user
+	if err := uc.profileRepo.InitIdealTraits(ctx, userID, result.Traits()); err != nil {
+		fmt.Printf("⚠️  [BigFive] Failed to seed ideal partner traits of user %d: %v\n", userID, err)
+	}
+
+	// Personality is part of the compatibility score
+	uc.decks.Invalidate(ctx, userID)
+
 	return result, nil
 }
 
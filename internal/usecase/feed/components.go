@@ -0,0 +1,151 @@
+package feed
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// defaultMaxDistanceKm is the distance at which the distance score reaches 0 if the user has no limit
+	defaultMaxDistanceKm = 100.0
+	// defaultActivityHalfLife is used if the activity component is created without a half-life
+	defaultActivityHalfLife = 72 * time.Hour
+)
+
+// PersonalityComponent compares the learned "ideal partner" vector of each side with the
+// measured Big Five traits of the other side and blends both directions:
+// how well they fit what I like and how well I fit what they like.
+// Can't be judged if neither direction can be computed.
+type PersonalityComponent struct{}
+
+func (PersonalityComponent) Name() string { return "personality" }
+
+func (PersonalityComponent) Score(in *ScoreInput) (float64, bool) {
+	myIdeal := in.Me.IdealTraits()
+	theirIdeal := in.Candidate.Profile.IdealTraits()
+
+	sum := 0.0
+	directions := 0
+
+	if myIdeal != nil && in.Candidate.Traits != nil {
+		sum += clamp01(myIdeal.Similarity(in.Candidate.Traits))
+		directions++
+	}
+	if theirIdeal != nil && in.MyTraits != nil {
+		sum += clamp01(theirIdeal.Similarity(in.MyTraits))
+		directions++
+	}
+
+	if directions == 0 {
+		return 0, false
+	}
+	return sum / float64(directions), true
+}
+
+// InterestsComponent is the Jaccard index of interests.
+// Can't be judged if neither side listed interests.
+type InterestsComponent struct{}
+
+func (InterestsComponent) Name() string { return "interests" }
+
+func (InterestsComponent) Score(in *ScoreInput) (float64, bool) {
+	mine := make(map[string]bool, len(in.Me.Interests))
+	for _, interest := range in.Me.Interests {
+		mine[interest] = true
+	}
+
+	union := len(mine)
+	common := 0
+	seen := make(map[string]bool, len(in.Candidate.Profile.Interests))
+	for _, interest := range in.Candidate.Profile.Interests {
+		if seen[interest] {
+			continue
+		}
+		seen[interest] = true
+		if mine[interest] {
+			common++
+		} else {
+			union++
+		}
+	}
+
+	if union == 0 {
+		return 0, false
+	}
+	return float64(common) / float64(union), true
+}
+
+// DistanceComponent decays linearly from 1 at 0 km to 0 at the user's max distance.
+// Can't be judged if either side has no location.
+type DistanceComponent struct{}
+
+func (DistanceComponent) Name() string { return "distance" }
+
+func (DistanceComponent) Score(in *ScoreInput) (float64, bool) {
+	if in.Candidate.DistanceKm == nil {
+		return 0, false
+	}
+
+	maxDist := defaultMaxDistanceKm
+	if in.Me.PrefMaxDistanceKm != nil && *in.Me.PrefMaxDistanceKm > 0 {
+		maxDist = float64(*in.Me.PrefMaxDistanceKm)
+	}
+
+	return clamp01(1 - *in.Candidate.DistanceKm/maxDist), true
+}
+
+// ActivityComponent prefers recently online users: 1 when online now,
+// halves every HalfLife offline. Can't be judged if the user was never online.
+type ActivityComponent struct {
+	HalfLife time.Duration
+}
+
+func (ActivityComponent) Name() string { return "activity" }
+
+func (c ActivityComponent) Score(in *ScoreInput) (float64, bool) {
+	if in.Candidate.LastOnlineAt == nil {
+		return 0, false
+	}
+
+	halfLife := c.HalfLife
+	if halfLife <= 0 {
+		halfLife = defaultActivityHalfLife
+	}
+
+	now := in.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	offline := now.Sub(*in.Candidate.LastOnlineAt)
+	if offline <= 0 {
+		return 1, true
+	}
+	return math.Pow(0.5, offline.Hours()/halfLife.Hours()), true
+}
+
+// EmbeddingComponent is the cosine similarity of interest embeddings mapped to 0..1.
+// Can't be judged if either embedding is missing or zero or they have different dimensions.
+type EmbeddingComponent struct{}
+
+func (EmbeddingComponent) Name() string { return "embedding" }
+
+func (EmbeddingComponent) Score(in *ScoreInput) (float64, bool) {
+	a, b := in.MyEmbedding, in.Candidate.Embedding
+	if len(a) == 0 || len(a) != len(b) {
+		return 0, false
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0, false
+	}
+
+	cosine := dot / (math.Sqrt(normA) * math.Sqrt(normB))
+	return clamp01((cosine + 1) / 2), true
+}
@@ -0,0 +1,224 @@
+package feed
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+)
+
+func newScoreInput() *ScoreInput {
+	return &ScoreInput{
+		Me:        &domain.Profile{},
+		Candidate: &domain.Candidate{Profile: &domain.Profile{}},
+		Now:       time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func assertScore(t *testing.T, gotScore float64, gotOK bool, wantScore float64, wantOK bool) {
+	t.Helper()
+	if gotOK != wantOK {
+		t.Fatalf("Score() ok = %t, want %t", gotOK, wantOK)
+	}
+	if wantOK && math.Abs(gotScore-wantScore) > 1e-9 {
+		t.Fatalf("Score() = %v, want %v", gotScore, wantScore)
+	}
+}
+
+func TestPersonalityComponent(t *testing.T) {
+	traits := &domain.Traits{Openness: 0.5, Conscientiousness: 0.5, Extraversion: 0.5, Agreeableness: 0.5, Neuroticism: 0.5}
+
+	tests := []struct {
+		name      string
+		prepare   func(in *ScoreInput)
+		wantScore float64
+		wantOK    bool
+	}{
+		{
+			name:    "nil traits on both sides",
+			prepare: func(in *ScoreInput) {},
+		},
+		{
+			name: "ideal without candidate traits",
+			prepare: func(in *ScoreInput) {
+				in.Me.SetIdealTraits(traits)
+			},
+		},
+		{
+			name: "candidate ideal without my traits",
+			prepare: func(in *ScoreInput) {
+				in.Candidate.Profile.SetIdealTraits(traits)
+			},
+		},
+		{
+			name: "one direction",
+			prepare: func(in *ScoreInput) {
+				in.Me.SetIdealTraits(traits)
+				in.Candidate.Traits = traits
+			},
+			wantScore: 1,
+			wantOK:    true,
+		},
+		{
+			name: "both directions are averaged",
+			prepare: func(in *ScoreInput) {
+				in.Me.SetIdealTraits(traits)
+				in.Candidate.Traits = traits
+				in.Candidate.Profile.SetIdealTraits(traits)
+				in.MyTraits = &domain.Traits{Openness: 0.5, Conscientiousness: 0.5, Extraversion: 0.5, Agreeableness: 0.5, Neuroticism: 0.5 - domain.MaxTraitsDistance/2}
+			},
+			wantScore: 0.75,
+			wantOK:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := newScoreInput()
+			tt.prepare(in)
+			score, ok := PersonalityComponent{}.Score(in)
+			assertScore(t, score, ok, tt.wantScore, tt.wantOK)
+		})
+	}
+}
+
+func TestInterestsComponent(t *testing.T) {
+	tests := []struct {
+		name      string
+		mine      []string
+		theirs    []string
+		wantScore float64
+		wantOK    bool
+	}{
+		{name: "both empty"},
+		{name: "nil and empty", mine: nil, theirs: []string{}},
+		{name: "only mine", mine: []string{"music"}, wantScore: 0, wantOK: true},
+		{name: "disjoint", mine: []string{"music"}, theirs: []string{"sport"}, wantScore: 0, wantOK: true},
+		{name: "same", mine: []string{"music", "travel"}, theirs: []string{"travel", "music"}, wantScore: 1, wantOK: true},
+		{name: "duplicates count once", mine: []string{"music", "music"}, theirs: []string{"music", "music", "sport"}, wantScore: 0.5, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := newScoreInput()
+			in.Me.Interests = tt.mine
+			in.Candidate.Profile.Interests = tt.theirs
+			score, ok := InterestsComponent{}.Score(in)
+			assertScore(t, score, ok, tt.wantScore, tt.wantOK)
+		})
+	}
+}
+
+func TestDistanceComponent(t *testing.T) {
+	km := func(v float64) *float64 { return &v }
+	limit := func(v int) *int { return &v }
+
+	tests := []struct {
+		name      string
+		distance  *float64
+		maxKm     *int
+		wantScore float64
+		wantOK    bool
+	}{
+		{name: "missing coordinates"},
+		{name: "missing coordinates with limit", maxKm: limit(10)},
+		{name: "same place", distance: km(0), wantScore: 1, wantOK: true},
+		{name: "half of default limit", distance: km(defaultMaxDistanceKm / 2), wantScore: 0.5, wantOK: true},
+		{name: "half of user limit", distance: km(5), maxKm: limit(10), wantScore: 0.5, wantOK: true},
+		{name: "beyond limit", distance: km(20), maxKm: limit(10), wantScore: 0, wantOK: true},
+		{name: "zero limit uses default", distance: km(defaultMaxDistanceKm / 4), maxKm: limit(0), wantScore: 0.75, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := newScoreInput()
+			in.Candidate.DistanceKm = tt.distance
+			in.Me.PrefMaxDistanceKm = tt.maxKm
+			score, ok := DistanceComponent{}.Score(in)
+			assertScore(t, score, ok, tt.wantScore, tt.wantOK)
+		})
+	}
+}
+
+func TestActivityComponent(t *testing.T) {
+	now := newScoreInput().Now
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	tests := []struct {
+		name       string
+		lastOnline *time.Time
+		halfLife   time.Duration
+		wantScore  float64
+		wantOK     bool
+	}{
+		{name: "never online"},
+		{name: "online now", lastOnline: at(0), wantScore: 1, wantOK: true},
+		{name: "clock skew", lastOnline: at(-time.Minute), wantScore: 1, wantOK: true},
+		{name: "one half-life", lastOnline: at(24 * time.Hour), halfLife: 24 * time.Hour, wantScore: 0.5, wantOK: true},
+		{name: "two half-lives", lastOnline: at(48 * time.Hour), halfLife: 24 * time.Hour, wantScore: 0.25, wantOK: true},
+		{name: "default half-life", lastOnline: at(defaultActivityHalfLife), wantScore: 0.5, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := newScoreInput()
+			in.Candidate.LastOnlineAt = tt.lastOnline
+			score, ok := ActivityComponent{HalfLife: tt.halfLife}.Score(in)
+			assertScore(t, score, ok, tt.wantScore, tt.wantOK)
+		})
+	}
+}
+
+func TestWeightedScorerScalesJudgedComponents(t *testing.T) {
+	in := newScoreInput()
+	in.Me.Interests = []string{"music"}
+	in.Candidate.Profile.Interests = []string{"music"}
+
+	// Only interests can be judged, so it takes the whole weight
+	score := NewScorer(Weights{Personality: 40, Interests: 30, Distance: 30}).Score(in)
+	if score.Total != 100 {
+		t.Fatalf("Total = %v, want 100", score.Total)
+	}
+	if len(score.Breakdown) != 1 || score.Breakdown[0].Name != "interests" || score.Breakdown[0].Weight != 1 {
+		t.Fatalf("Breakdown = %+v, want only interests with weight 1", score.Breakdown)
+	}
+
+	empty := NewScorer(Weights{Personality: 40}).Score(newScoreInput())
+	if empty.Total != 0 || len(empty.Breakdown) != 0 {
+		t.Fatalf("Score() without judged components = %+v, want empty", empty)
+	}
+}
+
+func TestEmbeddingComponent(t *testing.T) {
+	tests := []struct {
+		name      string
+		mine      []float32
+		theirs    []float32
+		wantScore float64
+		wantOK    bool
+	}{
+		{name: "both missing"},
+		{name: "my vector missing", theirs: []float32{1, 0}},
+		{name: "candidate vector missing", mine: []float32{1, 0}},
+		{name: "zero vector", mine: []float32{0, 0}, theirs: []float32{1, 0}},
+		{name: "zero candidate vector", mine: []float32{1, 0}, theirs: []float32{0, 0}},
+		{name: "mismatched lengths", mine: []float32{1, 0}, theirs: []float32{1, 0, 0}},
+		{name: "same direction", mine: []float32{1, 2}, theirs: []float32{2, 4}, wantScore: 1, wantOK: true},
+		{name: "orthogonal", mine: []float32{1, 0}, theirs: []float32{0, 3}, wantScore: 0.5, wantOK: true},
+		{name: "opposite", mine: []float32{1, -1}, theirs: []float32{-1, 1}, wantScore: 0, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := newScoreInput()
+			in.MyEmbedding = tt.mine
+			in.Candidate.Embedding = tt.theirs
+			score, ok := EmbeddingComponent{}.Score(in)
+			assertScore(t, score, ok, tt.wantScore, tt.wantOK)
+		})
+	}
+}
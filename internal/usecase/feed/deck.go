@@ -0,0 +1,254 @@
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/cache"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultDeckPageSize is the number of cards returned by default
+	DefaultDeckPageSize = 10
+	// MaxDeckPageSize is the max number of cards returned at once
+	MaxDeckPageSize = 50
+	// deckTTL is how long a computed deck is served before it is ranked again
+	deckTTL = 15 * time.Minute
+)
+
+// deck is a ranked list of candidates computed once and served page by page
+type deck struct {
+	ID     string              `json:"id"`
+	Cursor int                 `json:"cursor"`
+	Users  []*FeedUserResponse `json:"users"`
+}
+
+// DeckPage represents a batch of cards taken from the deck
+type DeckPage struct {
+	DeckID string              `json:"deck_id"`
+	Users  []*FeedUserResponse `json:"users"`
+	// Cursor is the position of the next card in the deck
+	Cursor    int  `json:"cursor"`
+	Remaining int  `json:"remaining"`
+	HasMore   bool `json:"has_more"`
+}
+
+// DeckCache keeps computed decks of users in the shared cache.
+// Use cases that change ranking inputs (preferences, personality) invalidate it.
+type DeckCache struct {
+	store cache.Store
+}
+
+// NewDeckCache creates a new deck cache
+func NewDeckCache(store cache.Store) *DeckCache {
+	return &DeckCache{
+		store: store,
+	}
+}
+
+// deckKey returns the cache key of the user's deck
+func deckKey(userID int) string {
+	return fmt.Sprintf("feed:deck:%d", userID)
+}
+
+// Invalidate drops the user's deck so the next request ranks candidates again
+func (c *DeckCache) Invalidate(ctx context.Context, userID int) {
+	if c == nil {
+		return
+	}
+	if err := c.store.Delete(ctx, deckKey(userID)); err != nil {
+		fmt.Printf("⚠️  [Feed] Failed to invalidate deck of user %d: %v\n", userID, err)
+	}
+}
+
+// decodeDeck parses a cached deck, nil if it is missing, broken or exhausted
+func decodeDeck(value string, exists bool) *deck {
+	if !exists {
+		return nil
+	}
+
+	var d deck
+	if err := json.Unmarshal([]byte(value), &d); err != nil || d.Cursor >= len(d.Users) {
+		return nil
+	}
+	return &d
+}
+
+// page returns the next size cards of the deck without moving its cursor
+func (d *deck) page(size int) *DeckPage {
+	end := d.Cursor + size
+	if end > len(d.Users) {
+		end = len(d.Users)
+	}
+
+	page := &DeckPage{
+		DeckID: d.ID,
+		Users:  d.Users[d.Cursor:end],
+		Cursor: end,
+	}
+	page.Remaining = len(d.Users) - end
+	page.HasMore = page.Remaining > 0
+	return page
+}
+
+// take atomically pops the next size cards of the user's cached deck.
+// fresh is used if there is no deck, nil is returned if there is neither.
+// Concurrent requests never get the same cards, even if both ranked a fresh deck.
+func (c *DeckCache) take(ctx context.Context, userID, size int, fresh *deck) *DeckPage {
+	if c == nil {
+		if fresh == nil {
+			return nil
+		}
+		return fresh.page(size)
+	}
+
+	var page *DeckPage
+	err := c.store.Update(ctx, deckKey(userID), deckTTL, func(value string, exists bool) (string, bool, error) {
+		page = nil
+
+		d := decodeDeck(value, exists)
+		if d == nil {
+			d = fresh
+		}
+		if d == nil {
+			return "", false, nil
+		}
+
+		page = d.page(size)
+		if !page.HasMore {
+			return "", false, nil
+		}
+
+		next := *d
+		next.Cursor = page.Cursor
+		data, err := json.Marshal(&next)
+		if err != nil {
+			return "", false, err
+		}
+		return string(data), true, nil
+	})
+	if err != nil {
+		// A deck that failed to save is ranked again on the next request
+		fmt.Printf("⚠️  [Feed] Failed to update deck of user %d: %v\n", userID, err)
+		if page == nil && fresh != nil {
+			page = fresh.page(size)
+		}
+	}
+	return page
+}
+
+// GetDeck returns the next size cards of the user's ranked deck.
+// The deck is ranked once and cached, subsequent calls move its cursor forward.
+// An exhausted or expired deck is ranked again, users swiped meanwhile are excluded.
+func (uc *FeedUseCase) GetDeck(ctx context.Context, userID, size int) (*DeckPage, error) {
+	if size <= 0 {
+		size = DefaultDeckPageSize
+	}
+	if size > MaxDeckPageSize {
+		size = MaxDeckPageSize
+	}
+
+	if page := uc.decks.take(ctx, userID, size, nil); page != nil {
+		return page, nil
+	}
+
+	ranked, err := uc.rankCandidates(ctx, userID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Another request may have ranked a deck meanwhile, it is served first
+	return uc.decks.take(ctx, userID, size, &deck{
+		ID:    uuid.NewString(),
+		Users: ranked,
+	}), nil
+}
+
+// Requeue puts the candidate at the top of the user's deck, e.g. after a swipe on them was undone.
+// Without a deck a new one is ranked with the candidate moved to the top.
+// Does nothing if the user is not a candidate anymore.
+func (uc *FeedUseCase) Requeue(ctx context.Context, userID, candidateID int) error {
+	if uc.decks == nil {
+		return nil
+	}
+
+	value, exists, err := uc.decks.store.Get(ctx, deckKey(userID))
+	if err != nil {
+		return fmt.Errorf("failed to load deck: %w", err)
+	}
+
+	current := decodeDeck(value, exists)
+	var fresh *deck
+	if current == nil {
+		ranked, err := uc.rankCandidates(ctx, userID, 0)
+		if err != nil {
+			return err
+		}
+		fresh = &deck{
+			ID:    uuid.NewString(),
+			Users: ranked,
+		}
+		current = fresh
+	}
+
+	// The card is ranked on its own only if the deck doesn't have it already
+	var ranked *FeedUserResponse
+	if findCard(current, candidateID) == nil {
+		cards, err := uc.rankCandidates(ctx, userID, candidateID)
+		if err != nil {
+			return err
+		}
+		if len(cards) > 0 {
+			ranked = cards[0]
+		}
+	}
+
+	return uc.decks.store.Update(ctx, deckKey(userID), deckTTL, func(value string, exists bool) (string, bool, error) {
+		d := decodeDeck(value, exists)
+		if d == nil {
+			d = fresh
+		}
+		if d == nil {
+			return value, exists, nil
+		}
+
+		card := findCard(d, candidateID)
+		if card == nil {
+			card = ranked
+		}
+		if card == nil {
+			return value, exists, nil
+		}
+
+		// Cards already served stay before the cursor, the candidate goes right after them
+		users := make([]*FeedUserResponse, 0, len(d.Users)+1)
+		users = append(users, d.Users[:d.Cursor]...)
+		users = append(users, card)
+		for _, u := range d.Users[d.Cursor:] {
+			if u.UserID != candidateID {
+				users = append(users, u)
+			}
+		}
+
+		next := *d
+		next.Users = users
+		data, err := json.Marshal(&next)
+		if err != nil {
+			return "", false, err
+		}
+		return string(data), true, nil
+	})
+}
+
+// findCard returns the card of the candidate among cards not served yet
+func findCard(d *deck, candidateID int) *FeedUserResponse {
+	for _, u := range d.Users[d.Cursor:] {
+		if u.UserID == candidateID {
+			return u
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/cache"
+)
+
+func TestDeckCacheTakeConcurrent(t *testing.T) {
+	const (
+		cards    = 100
+		pageSize = 3
+		workers  = 20
+	)
+
+	users := make([]*FeedUserResponse, 0, cards)
+	for i := 1; i <= cards; i++ {
+		users = append(users, &FeedUserResponse{UserID: i})
+	}
+
+	decks := NewDeckCache(cache.NewMemoryStore())
+	ctx := context.Background()
+
+	var (
+		mu      sync.Mutex
+		served  = make(map[int]int)
+		deckIDs = make(map[string]bool)
+	)
+	record := func(page *DeckPage) {
+		mu.Lock()
+		defer mu.Unlock()
+		deckIDs[page.DeckID] = true
+		for _, u := range page.Users {
+			served[u.UserID]++
+		}
+	}
+
+	// Every worker ranked its own deck at the same time, only one of them may be used
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			record(decks.take(ctx, 1, pageSize, &deck{ID: fmt.Sprintf("deck-%d", w), Users: users}))
+		}(w)
+	}
+	wg.Wait()
+
+	// Then they pop the shared deck until it is exhausted
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				page := decks.take(ctx, 1, pageSize, nil)
+				if page == nil {
+					return
+				}
+				record(page)
+				if !page.HasMore {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(deckIDs) != 1 {
+		t.Fatalf("cards served from %d decks, want 1", len(deckIDs))
+	}
+	if len(served) != cards {
+		t.Fatalf("served %d distinct cards, want %d", len(served), cards)
+	}
+	for id, count := range served {
+		if count != 1 {
+			t.Fatalf("card %d served %d times", id, count)
+		}
+	}
+}
+
+func TestDeckCacheTakeExhausted(t *testing.T) {
+	decks := NewDeckCache(cache.NewMemoryStore())
+	ctx := context.Background()
+	fresh := &deck{ID: "deck", Users: []*FeedUserResponse{{UserID: 1}, {UserID: 2}}}
+
+	page := decks.take(ctx, 1, 5, fresh)
+	if len(page.Users) != 2 || page.HasMore || page.Remaining != 0 {
+		t.Fatalf("take() = %d cards, has more %t, remaining %d", len(page.Users), page.HasMore, page.Remaining)
+	}
+	if page := decks.take(ctx, 1, 5, nil); page != nil {
+		t.Fatalf("take() after exhausted deck = %+v, want nil", page)
+	}
+}
@@ -5,26 +5,46 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"time"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/domain"
 	"github.com/gdugdh24/mpit2026-backend/internal/repository"
 )
 
+// massLikerPenalty scales the ranking score of users flagged for liking almost everyone
+const massLikerPenalty = 0.5
+
+// feedCandidatePoolSize is the number of candidates scored to pick the next user.
+// Swiped users are excluded in the query, so the pool moves on as the user swipes.
+const feedCandidatePoolSize = 200
+
 type FeedUseCase struct {
-	userRepo    repository.UserRepository
-	profileRepo repository.ProfileRepository
-	swipeRepo   repository.SwipeRepository
+	userRepo      repository.UserRepository
+	profileRepo   repository.ProfileRepository
+	swipeRepo     repository.SwipeRepository
+	bigFiveRepo   repository.BigFiveRepository
+	embeddingRepo repository.EmbeddingRepository
+	scorer        Scorer
+	decks         *DeckCache
 }
 
 func NewFeedUseCase(
 	userRepo repository.UserRepository,
 	profileRepo repository.ProfileRepository,
 	swipeRepo repository.SwipeRepository,
+	bigFiveRepo repository.BigFiveRepository,
+	embeddingRepo repository.EmbeddingRepository,
+	scorer Scorer,
+	decks *DeckCache,
 ) *FeedUseCase {
 	return &FeedUseCase{
-		userRepo:    userRepo,
-		profileRepo: profileRepo,
-		swipeRepo:   swipeRepo,
+		userRepo:      userRepo,
+		profileRepo:   profileRepo,
+		swipeRepo:     swipeRepo,
+		bigFiveRepo:   bigFiveRepo,
+		embeddingRepo: embeddingRepo,
+		scorer:        scorer,
+		decks:         decks,
 	}
 }
 
@@ -39,10 +59,30 @@ type FeedUserResponse struct {
 	Interests          []string `json:"interests"`
 	DistanceKm         *float64 `json:"distance_km,omitempty"`
 	CompatibilityScore int      `json:"compatibility_score"`
+	// ScoreBreakdown explains the score: components that could be judged and their shares
+	ScoreBreakdown []ScoreComponent `json:"score_breakdown"`
+	// SuperLikedYou is true if the user super liked the feed owner, such cards go first
+	SuperLikedYou bool `json:"super_liked_you"`
 }
 
 // GetNextUser returns the next user for feed
 func (uc *FeedUseCase) GetNextUser(ctx context.Context, currentUserID int) (*FeedUserResponse, error) {
+	ranked, err := uc.rankCandidates(ctx, currentUserID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ranked) > 0 {
+		return ranked[0], nil
+	}
+
+	// No more users in feed
+	return nil, nil
+}
+
+// rankCandidates returns candidates for the user's feed, best compatibility score first.
+// If candidateID is set only that user is ranked (if they are still a candidate).
+func (uc *FeedUseCase) rankCandidates(ctx context.Context, currentUserID, candidateID int) ([]*FeedUserResponse, error) {
 	// Get current user's profile for preferences
 	currentProfile, err := uc.profileRepo.GetByUserID(ctx, currentUserID)
 	if err != nil {
@@ -55,243 +95,107 @@ func (uc *FeedUseCase) GetNextUser(ctx context.Context, currentUserID int) (*Fee
 		return nil, fmt.Errorf("failed to get current user: %w", err)
 	}
 
-	// Build filters based on preferences
-	filters := make(map[string]interface{})
-
-	// Filter by onboarding complete
-	filters["is_onboarding_complete"] = true
-
-	// Filter by city if set
-	if currentProfile.City != nil && *currentProfile.City != "" {
-		filters["city"] = *currentProfile.City
+	// Current user's measured personality, nil until the Big Five test is taken
+	var myTraits *domain.Traits
+	if result, err := uc.bigFiveRepo.GetByUserID(ctx, currentUserID); err == nil {
+		myTraits = result.Traits()
 	}
 
-	// Get candidate profiles (exclude already swiped)
-	candidates, err := uc.profileRepo.SearchProfiles(ctx, filters, 100, 0)
+	// Interest embedding, the embedding component is skipped without it
+	myEmbedding, err := uc.embeddingRepo.GetCombinedVector(ctx, currentUserID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search profiles: %w", err)
+		fmt.Printf("⚠️  [Feed] Failed to get embedding of user %d: %v\n", currentUserID, err)
 	}
 
-	// Filter candidates and calculate scores
-	type ScoredCandidate struct {
-		Profile *domain.Profile
-		Score   float64
-		User    *domain.User
+	// Build filters based on preferences
+	interestedIn := currentProfile.InterestedIn
+	if len(interestedIn) == 0 {
+		interestedIn = domain.DefaultInterestedIn(currentUser.Gender)
 	}
-	var scoredCandidates []ScoredCandidate
-
-	for _, candidate := range candidates {
-		// Skip self
-		if candidate.UserID == currentUserID {
-			continue
-		}
-
-		// Check if already swiped
-		existingSwipe, err := uc.swipeRepo.GetByUsers(ctx, currentUserID, candidate.UserID)
-		if err == nil && existingSwipe != nil {
-			continue // Already swiped
-		}
-
-		// Get candidate user for age
-		candidateUser, err := uc.userRepo.GetByID(ctx, candidate.UserID)
-		if err != nil {
-			continue
-		}
-
-		// Check age preferences
-		age := candidateUser.Age()
-		if currentProfile.PrefMinAge != nil && age < *currentProfile.PrefMinAge {
-			continue
-		}
-		if currentProfile.PrefMaxAge != nil && age > *currentProfile.PrefMaxAge {
-			continue
-		}
-
-		// Check gender preferences (if we add gender preference later)
-		// For now, just check opposite gender
-		if currentUser.Gender == candidateUser.Gender {
-			continue // Skip same gender for now
-		}
-
-		// Calculate distance if locations available
-		var distanceKm *float64
-		if currentProfile.LocationLat != nil && currentProfile.LocationLon != nil &&
-			candidate.LocationLat != nil && candidate.LocationLon != nil {
-			distance := calculateDistance(
-				*currentProfile.LocationLat, *currentProfile.LocationLon,
-				*candidate.LocationLat, *candidate.LocationLon,
-			)
 
-			// Check distance preference
-			if currentProfile.PrefMaxDistanceKm != nil && distance > float64(*currentProfile.PrefMaxDistanceKm) {
-				continue
-			}
-
-			distanceKm = &distance
-		}
-
-		// Calculate Compatibility Score
-		score := uc.calculateCompatibilityScore(currentProfile, candidate, distanceKm)
-		scoredCandidates = append(scoredCandidates, ScoredCandidate{
-			Profile: candidate,
-			Score:   score,
-			User:    candidateUser,
-		})
+	filter := &domain.CandidateFilter{
+		UserID:        currentUserID,
+		CandidateID:   candidateID,
+		Genders:       interestedIn,
+		SeekerGender:  currentUser.Gender,
+		MinAge:        currentProfile.PrefMinAge,
+		MaxAge:        currentProfile.PrefMaxAge,
+		Lat:           currentProfile.LocationLat,
+		Lon:           currentProfile.LocationLon,
+		MaxDistanceKm: currentProfile.PrefMaxDistanceKm,
 	}
 
-	// Sort by score descending
-	sort.Slice(scoredCandidates, func(i, j int) bool {
-		return scoredCandidates[i].Score > scoredCandidates[j].Score
-	})
-
-	// Return top candidate
-	if len(scoredCandidates) > 0 {
-		best := scoredCandidates[0]
-
-		// Calculate distance again for response (optimization: could store it)
-		var distanceKm *float64
-		if currentProfile.LocationLat != nil && currentProfile.LocationLon != nil &&
-			best.Profile.LocationLat != nil && best.Profile.LocationLon != nil {
-			d := calculateDistance(
-				*currentProfile.LocationLat, *currentProfile.LocationLon,
-				*best.Profile.LocationLat, *best.Profile.LocationLon,
-			)
-			distanceKm = &d
-		}
-
-		return &FeedUserResponse{
-			ID:                 best.Profile.ID,
-			UserID:             best.Profile.UserID,
-			DisplayName:        best.Profile.DisplayName,
-			Bio:                best.Profile.Bio,
-			City:               best.Profile.City,
-			Age:                best.User.Age(),
-			Interests:          best.Profile.Interests,
-			DistanceKm:         distanceKm,
-			CompatibilityScore: int(best.Score), // Add this field to response
-		}, nil
+	// Without coordinates the city is the only way to show someone nearby
+	if (currentProfile.LocationLat == nil || currentProfile.LocationLon == nil) &&
+		currentProfile.City != nil && *currentProfile.City != "" {
+		filter.City = currentProfile.City
 	}
 
-	// No more users in feed
-	return nil, nil
-}
-
-// calculateCompatibilityScore calculates a 0-100 score
-func (uc *FeedUseCase) calculateCompatibilityScore(me, candidate *domain.Profile, distanceKm *float64) float64 {
-	score := 0.0
-
-	// 1. Personality Compatibility (40%)
-	// Compare My Ideal (Preferences) vs Candidate's Real (Traits)
-	// If I don't have preferences yet (new user), use my own traits (assume looking for similar)
-	personalityScore := 0.0
-	if me.PrefOpenness != nil && candidate.PrefOpenness != nil {
-		// Euclidean distance between My Ideal and Candidate's Real
-		// Since we don't have separate "Real" vs "Ideal" columns for candidate yet (we reused same cols for now or need to clarify),
-		// let's assume the columns in DB represent the user's traits primarily,
-		// and we use them as "Ideal" for the searcher and "Real" for the candidate.
-		// Wait, the migration added `pref_` columns.
-		// Let's assume `pref_` columns store the "Ideal" for the user.
-		// But where are the "Real" traits stored?
-		// Ah, in `big_five_results` table!
-		// But `Profile` struct doesn't have them joined.
-		// For MVP simplicity: Let's assume `pref_` columns are initialized with "Real" traits
-		// and then evolve to become "Ideal".
-		// So we compare `me.Pref` vs `candidate.Pref`.
-
-		dist := 0.0
-		dist += math.Pow(*me.PrefOpenness-*candidate.PrefOpenness, 2)
-		dist += math.Pow(*me.PrefConscientiousness-*candidate.PrefConscientiousness, 2)
-		dist += math.Pow(*me.PrefExtraversion-*candidate.PrefExtraversion, 2)
-		dist += math.Pow(*me.PrefAgreeableness-*candidate.PrefAgreeableness, 2)
-		dist += math.Pow(*me.PrefNeuroticism-*candidate.PrefNeuroticism, 2)
-		dist = math.Sqrt(dist)
-
-		// Max distance is sqrt(1^2 * 5) = sqrt(5) = 2.23
-		// Normalize to 0-1 (1 means close, 0 means far)
-		personalityScore = 1.0 - (dist / 2.23)
-		if personalityScore < 0 {
-			personalityScore = 0
-		}
-	} else {
-		// Fallback: Neutral score
-		personalityScore = 0.5
+	// Get candidates not swiped yet, nearest first
+	candidates, err := uc.profileRepo.GetFeedCandidates(ctx, filter, feedCandidatePoolSize, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed candidates: %w", err)
 	}
-	score += personalityScore * 40
 
-	// 2. Interests Compatibility (30%)
-	// Jaccard Index
-	interestsScore := 0.0
-	common := 0
-	total := len(me.Interests) + len(candidate.Interests)
-	if total > 0 {
-		// Simple intersection check
-		for _, myInt := range me.Interests {
-			for _, theirInt := range candidate.Interests {
-				if myInt == theirInt {
-					common++
-					break
-				}
-			}
-		}
-		// Union = Total - Common
-		union := total - common
-		if union > 0 {
-			interestsScore = float64(common) / float64(union)
+	now := time.Now()
+	ranked := make([]*FeedUserResponse, 0, len(candidates))
+	scores := make(map[int]float64, len(candidates))
+	for _, candidate := range candidates {
+		score := uc.scorer.Score(&ScoreInput{
+			Me:          currentProfile,
+			MyTraits:    myTraits,
+			MyEmbedding: myEmbedding,
+			Candidate:   candidate,
+			Now:         now,
+		})
+		// The shown score stays as is, only the ranking is demoted
+		rank := score.Total
+		if candidate.MassLiker {
+			rank *= massLikerPenalty
 		}
+		scores[candidate.Profile.UserID] = rank
+		ranked = append(ranked, &FeedUserResponse{
+			ID:                 candidate.Profile.ID,
+			UserID:             candidate.Profile.UserID,
+			DisplayName:        candidate.Profile.DisplayName,
+			Bio:                candidate.Profile.Bio,
+			City:               candidate.Profile.City,
+			Age:                candidate.Age(),
+			Interests:          candidate.Profile.Interests,
+			DistanceKm:         candidate.DistanceKm,
+			CompatibilityScore: int(math.Round(score.Total)),
+			ScoreBreakdown:     score.Breakdown,
+			SuperLikedYou:      candidate.SuperLikedMe,
+		})
 	}
-	score += interestsScore * 30
 
-	// 3. Demographics/Distance (30%)
-	demoScore := 1.0
-	if distanceKm != nil {
-		// Decay score as distance increases
-		// e.g. 0km = 1.0, 100km = 0.0
-		// Linear decay for simplicity
-		maxDist := 100.0
-		if me.PrefMaxDistanceKm != nil {
-			maxDist = float64(*me.PrefMaxDistanceKm)
-		}
-		distScore := 1.0 - (*distanceKm / maxDist)
-		if distScore < 0 {
-			distScore = 0
+	// Super likes first, then by score descending, equal scores keep the query order (nearest first)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].SuperLikedYou != ranked[j].SuperLikedYou {
+			return ranked[i].SuperLikedYou
 		}
-		demoScore = distScore
-	}
-	score += demoScore * 30
+		return scores[ranked[i].UserID] > scores[ranked[j].UserID]
+	})
 
-	return score
+	return ranked, nil
 }
 
-// Helper functions (duplicated from swipe usecase, should be in shared utils)
-func calculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
-	const earthRadius = 6371.0
-	dLat := (lat2 - lat1) * (math.Pi / 180.0)
-	dLon := (lon2 - lon1) * (math.Pi / 180.0)
-	lat1Rad := lat1 * (math.Pi / 180.0)
-	lat2Rad := lat2 * (math.Pi / 180.0)
-	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Sin(dLon/2)*math.Sin(dLon/2)*math.Cos(lat1Rad)*math.Cos(lat2Rad)
-	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-	return earthRadius * c
-}
+// ResetDislikes deletes dislikes of the user so the disliked users return to the feed.
+// If olderThan is positive, only dislikes made more than olderThan ago are deleted.
+func (uc *FeedUseCase) ResetDislikes(ctx context.Context, userID int, olderThan time.Duration) (int, error) {
+	var before *time.Time
+	if olderThan > 0 {
+		t := time.Now().Add(-olderThan)
+		before = &t
+	}
 
-// ResetDislikes deletes all dislikes for a user to refresh the feed
-func (uc *FeedUseCase) ResetDislikes(ctx context.Context, userID int) (int, error) {
-	// Get all user swipes
-	swipes, err := uc.swipeRepo.GetUserSwipes(ctx, userID, 1000, 0)
+	count, err := uc.swipeRepo.DeleteDislikes(ctx, userID, before)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get swipes: %w", err)
+		return 0, fmt.Errorf("failed to delete dislikes: %w", err)
 	}
 
-	count := 0
-	// Note: This is a simplified implementation
-	// In production, you'd want a batch delete method in repository
-	for _, swipe := range swipes {
-		if !swipe.IsLike {
-			// Delete dislike swipes
-			// For now, we'll just count them
-			// You'd need to implement a Delete method in swipe repository
-			count++
-		}
+	if count > 0 {
+		uc.decks.Invalidate(ctx, userID)
 	}
 
 	return count, nil
@@ -0,0 +1,132 @@
+package feed
+
+import (
+	"math"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+)
+
+// ScoreInput is everything scorers know about the feed owner and a candidate
+type ScoreInput struct {
+	Me       *domain.Profile
+	MyTraits *domain.Traits
+	// MyEmbedding is the combined interest embedding of the feed owner, nil if not computed
+	MyEmbedding []float32
+	Candidate   *domain.Candidate
+	Now         time.Time
+}
+
+// Component scores one aspect of compatibility in the 0..1 range.
+// ok is false if the aspect can't be judged for the pair (e.g. no location),
+// then the component is left out and the other weights are scaled up.
+type Component interface {
+	Name() string
+	Score(in *ScoreInput) (score float64, ok bool)
+}
+
+// Scorer calculates compatibility of a candidate with the feed owner
+type Scorer interface {
+	Score(in *ScoreInput) *Score
+}
+
+// Score is a 0-100 compatibility score with its breakdown
+type Score struct {
+	Total     float64
+	Breakdown []ScoreComponent
+}
+
+// ScoreComponent explains the contribution of one component to the score
+type ScoreComponent struct {
+	Name string `json:"name"`
+	// Score is the component score, 0-100
+	Score int `json:"score"`
+	// Weight is the share of the component in the total score, 0..1
+	Weight float64 `json:"weight"`
+}
+
+// WeightedComponent is a component with its weight in the total score
+type WeightedComponent struct {
+	Component
+	Weight float64
+}
+
+// WeightedScorer is a weighted average of components
+type WeightedScorer struct {
+	components []WeightedComponent
+}
+
+// NewWeightedScorer creates a scorer, components with non-positive weight are dropped
+func NewWeightedScorer(components ...WeightedComponent) *WeightedScorer {
+	s := &WeightedScorer{}
+	for _, c := range components {
+		if c.Weight > 0 {
+			s.components = append(s.components, c)
+		}
+	}
+	return s
+}
+
+func (s *WeightedScorer) Score(in *ScoreInput) *Score {
+	type judged struct {
+		name   string
+		score  float64
+		weight float64
+	}
+
+	var parts []judged
+	totalWeight := 0.0
+	for _, c := range s.components {
+		score, ok := c.Score(in)
+		if !ok {
+			continue
+		}
+		parts = append(parts, judged{name: c.Name(), score: clamp01(score), weight: c.Weight})
+		totalWeight += c.Weight
+	}
+
+	result := &Score{
+		Breakdown: make([]ScoreComponent, 0, len(parts)),
+	}
+	if totalWeight == 0 {
+		return result
+	}
+
+	for _, p := range parts {
+		share := p.weight / totalWeight
+		result.Total += p.score * share * 100
+		result.Breakdown = append(result.Breakdown, ScoreComponent{
+			Name:   p.name,
+			Score:  int(math.Round(p.score * 100)),
+			Weight: math.Round(share*100) / 100,
+		})
+	}
+
+	return result
+}
+
+// Weights configures the default scorer
+type Weights struct {
+	Personality float64
+	Interests   float64
+	Distance    float64
+	Activity    float64
+	Embedding   float64
+	// ActivityHalfLife is the time offline after which the activity score halves
+	ActivityHalfLife time.Duration
+}
+
+// NewScorer creates the default scorer with the given weights
+func NewScorer(w Weights) Scorer {
+	return NewWeightedScorer(
+		WeightedComponent{Component: PersonalityComponent{}, Weight: w.Personality},
+		WeightedComponent{Component: InterestsComponent{}, Weight: w.Interests},
+		WeightedComponent{Component: DistanceComponent{}, Weight: w.Distance},
+		WeightedComponent{Component: ActivityComponent{HalfLife: w.ActivityHalfLife}, Weight: w.Activity},
+		WeightedComponent{Component: EmbeddingComponent{}, Weight: w.Embedding},
+	)
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
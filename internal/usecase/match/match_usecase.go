@@ -0,0 +1,258 @@
+package match
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/realtime"
+	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+)
+
+// lastMessagePreviewLength is the max number of characters shown in the match list
+const lastMessagePreviewLength = 100
+
+type MatchUseCase struct {
+	matchRepo   repository.MatchRepository
+	profileRepo repository.ProfileRepository
+	userRepo    repository.UserRepository
+	messageRepo repository.MessageRepository
+	publisher   *realtime.Publisher
+}
+
+func NewMatchUseCase(
+	matchRepo repository.MatchRepository,
+	profileRepo repository.ProfileRepository,
+	userRepo repository.UserRepository,
+	messageRepo repository.MessageRepository,
+	publisher *realtime.Publisher,
+) *MatchUseCase {
+	return &MatchUseCase{
+		matchRepo:   matchRepo,
+		profileRepo: profileRepo,
+		userRepo:    userRepo,
+		messageRepo: messageRepo,
+		publisher:   publisher,
+	}
+}
+
+// MatchUserProfile represents the other user's profile card in a match
+type MatchUserProfile struct {
+	ID           int        `json:"id"`
+	UserID       int        `json:"user_id"`
+	DisplayName  string     `json:"display_name"`
+	Bio          *string    `json:"bio"`
+	City         *string    `json:"city"`
+	Age          int        `json:"age"`
+	Interests    []string   `json:"interests"`
+	DistanceKm   *float64   `json:"distance_km,omitempty"`
+	IsOnline     bool       `json:"is_online"`
+	LastOnlineAt *time.Time `json:"last_online_at"`
+}
+
+// LastMessagePreview represents the latest message in a match
+type LastMessagePreview struct {
+	ID        int       `json:"id"`
+	Content   string    `json:"content"`
+	SenderID  int       `json:"sender_id"`
+	IsRead    bool      `json:"is_read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MatchResponse represents a match in the match list
+type MatchResponse struct {
+	MatchID        int                 `json:"match_id"`
+	User           *MatchUserProfile   `json:"user"`
+	MatchedAt      time.Time           `json:"matched_at"`
+	LastMessage    *LastMessagePreview `json:"last_message"`
+	UnreadMessages int                 `json:"unread_messages"`
+}
+
+// MatchDetailResponse represents a single match with AI Wingman content
+type MatchDetailResponse struct {
+	*MatchResponse
+	Explanation *string `json:"explanation"`
+	// Icebreakers are opening lines for the current user
+	Icebreakers []string `json:"icebreakers"`
+}
+
+// GetMatches returns active matches of the user with the other user's profile card
+func (uc *MatchUseCase) GetMatches(ctx context.Context, userID int, limit, offset int) ([]*MatchResponse, int, error) {
+	matches, err := uc.matchRepo.GetUserMatches(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get matches: %w", err)
+	}
+
+	total, err := uc.matchRepo.CountUserMatches(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count matches: %w", err)
+	}
+
+	// Current user's profile is needed for distance calculation
+	currentProfile, err := uc.profileRepo.GetByUserID(ctx, userID)
+	if err != nil && err != domain.ErrProfileNotFound {
+		return nil, 0, fmt.Errorf("failed to get current user profile: %w", err)
+	}
+
+	responses := make([]*MatchResponse, 0, len(matches))
+	for _, m := range matches {
+		resp, err := uc.buildMatchResponse(ctx, m, userID, currentProfile)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to build match %d: %w", m.ID, err)
+		}
+		responses = append(responses, resp)
+	}
+
+	return responses, total, nil
+}
+
+// GetMatch returns a single match if the user participates in it
+func (uc *MatchUseCase) GetMatch(ctx context.Context, userID, matchID int) (*MatchDetailResponse, error) {
+	m, err := uc.getParticipantMatch(ctx, userID, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentProfile, err := uc.profileRepo.GetByUserID(ctx, userID)
+	if err != nil && err != domain.ErrProfileNotFound {
+		return nil, fmt.Errorf("failed to get current user profile: %w", err)
+	}
+
+	resp, err := uc.buildMatchResponse(ctx, m, userID, currentProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MatchDetailResponse{
+		MatchResponse: resp,
+		Explanation:   m.Explanation,
+		Icebreakers:   m.IcebreakersFor(userID),
+	}, nil
+}
+
+// Unmatch deactivates the match for both users
+func (uc *MatchUseCase) Unmatch(ctx context.Context, userID, matchID int) error {
+	m, err := uc.getParticipantMatch(ctx, userID, matchID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.matchRepo.Deactivate(ctx, m.ID, domain.MatchDeactivatedByUnmatch); err != nil {
+		return fmt.Errorf("failed to deactivate match: %w", err)
+	}
+
+	uc.publisher.Publish(ctx, realtime.EventMatchRemoved, realtime.MatchRemovedPayload{
+		MatchID: m.ID,
+	}, m.User1ID, m.User2ID)
+
+	return nil
+}
+
+// getParticipantMatch loads an active match and checks that the user participates in it
+func (uc *MatchUseCase) getParticipantMatch(ctx context.Context, userID, matchID int) (*domain.Match, error) {
+	m, err := uc.matchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.HasUser(userID) {
+		return nil, domain.ErrForbidden
+	}
+
+	if !m.IsActive {
+		return nil, domain.ErrNotMatched
+	}
+
+	return m, nil
+}
+
+// buildMatchResponse assembles the match card as seen by userID
+func (uc *MatchUseCase) buildMatchResponse(ctx context.Context, m *domain.Match, userID int, currentProfile *domain.Profile) (*MatchResponse, error) {
+	otherUserID, ok := m.GetOtherUserID(userID)
+	if !ok {
+		return nil, domain.ErrForbidden
+	}
+
+	otherProfile, err := uc.profileRepo.GetByUserID(ctx, otherUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	otherUser, err := uc.userRepo.GetByID(ctx, otherUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var distanceKm *float64
+	if currentProfile != nil && currentProfile.LocationLat != nil && currentProfile.LocationLon != nil &&
+		otherProfile.LocationLat != nil && otherProfile.LocationLon != nil {
+		distance := calculateDistance(
+			*currentProfile.LocationLat, *currentProfile.LocationLon,
+			*otherProfile.LocationLat, *otherProfile.LocationLon,
+		)
+		distanceKm = &distance
+	}
+
+	resp := &MatchResponse{
+		MatchID: m.ID,
+		User: &MatchUserProfile{
+			ID:           otherProfile.ID,
+			UserID:       otherUserID,
+			DisplayName:  otherProfile.DisplayName,
+			Bio:          otherProfile.Bio,
+			City:         otherProfile.City,
+			Age:          otherUser.Age(),
+			Interests:    otherProfile.Interests,
+			DistanceKm:   distanceKm,
+			IsOnline:     otherUser.IsOnline,
+			LastOnlineAt: otherUser.LastOnlineAt,
+		},
+		MatchedAt: m.CreatedAt,
+	}
+
+	lastMessage, err := uc.messageRepo.GetLastMessage(ctx, m.ID)
+	if err != nil && !errors.Is(err, domain.ErrMessageNotFound) {
+		return nil, fmt.Errorf("failed to get last message: %w", err)
+	}
+	if lastMessage != nil {
+		resp.LastMessage = &LastMessagePreview{
+			ID:        lastMessage.ID,
+			Content:   truncate(lastMessage.Content, lastMessagePreviewLength),
+			SenderID:  lastMessage.SenderID,
+			IsRead:    lastMessage.IsRead,
+			CreatedAt: lastMessage.CreatedAt,
+		}
+	}
+
+	unread, err := uc.messageRepo.GetMatchUnreadCount(ctx, m.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unread count: %w", err)
+	}
+	resp.UnreadMessages = unread
+
+	return resp, nil
+}
+
+// truncate cuts the string to maxLen runes adding an ellipsis
+func truncate(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen]) + "…"
+}
+
+// Helper functions (duplicated from feed usecase, should be in shared utils)
+func calculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371.0
+	dLat := (lat2 - lat1) * (math.Pi / 180.0)
+	dLon := (lon2 - lon1) * (math.Pi / 180.0)
+	lat1Rad := lat1 * (math.Pi / 180.0)
+	lat2Rad := lat2 * (math.Pi / 180.0)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Sin(dLon/2)*math.Sin(dLon/2)*math.Cos(lat1Rad)*math.Cos(lat2Rad)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+	return earthRadius * c
+}
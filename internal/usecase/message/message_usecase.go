@@ -0,0 +1,198 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/realtime"
+	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/notification"
+)
+
+// MaxMessageLength is the max message length in characters
+const MaxMessageLength = 2000
+
+type MessageUseCase struct {
+	messageRepo repository.MessageRepository
+	matchRepo   repository.MatchRepository
+	publisher   *realtime.Publisher
+	notifier    *notification.Notifier
+}
+
+func NewMessageUseCase(
+	messageRepo repository.MessageRepository,
+	matchRepo repository.MatchRepository,
+	publisher *realtime.Publisher,
+	notifier *notification.Notifier,
+) *MessageUseCase {
+	return &MessageUseCase{
+		messageRepo: messageRepo,
+		matchRepo:   matchRepo,
+		publisher:   publisher,
+		notifier:    notifier,
+	}
+}
+
+// SendMessageRequest represents a new message
+type SendMessageRequest struct {
+	// Content is not required by binding: SendMessage reports empty input as ErrEmptyMessage
+	Content string `json:"content"`
+}
+
+// MarkReadRequest represents a read receipt up to a message
+type MarkReadRequest struct {
+	UpToMessageID int `json:"up_to_message_id" binding:"required,min=1"`
+}
+
+// MessagesPage represents a page of messages, newest first
+type MessagesPage struct {
+	Messages   []*domain.Message `json:"messages"`
+	NextCursor *int              `json:"next_cursor"`
+	HasMore    bool              `json:"has_more"`
+}
+
+// SendMessage sends a message to an active match
+func (uc *MessageUseCase) SendMessage(ctx context.Context, senderID, matchID int, req *SendMessageRequest) (*domain.Message, error) {
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, domain.ErrEmptyMessage
+	}
+	if utf8.RuneCountInString(content) > MaxMessageLength {
+		return nil, domain.ErrMessageTooLong
+	}
+
+	m, err := uc.getParticipantMatch(ctx, senderID, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &domain.Message{
+		MatchID:  matchID,
+		SenderID: senderID,
+		Content:  content,
+		IsRead:   false,
+	}
+
+	if err := uc.messageRepo.Create(ctx, message); err != nil {
+		return nil, fmt.Errorf("failed to create message: %w", err)
+	}
+
+	// Deliver to the recipient and to the sender's other devices
+	uc.publisher.Publish(ctx, realtime.EventNewMessage, message, m.User1ID, m.User2ID)
+
+	recipientID, _ := m.GetOtherUserID(senderID)
+	uc.notifier.NotifyNewMessage(recipientID, message)
+
+	return message, nil
+}
+
+// GetMessages returns messages of the match older than beforeID (0 = latest)
+func (uc *MessageUseCase) GetMessages(ctx context.Context, userID, matchID, beforeID, limit int) (*MessagesPage, error) {
+	if _, err := uc.getParticipantMatch(ctx, userID, matchID); err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra message to know if there are more
+	messages, err := uc.messageRepo.GetMatchMessagesBefore(ctx, matchID, beforeID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	page := &MessagesPage{
+		Messages: messages,
+	}
+
+	if len(messages) > limit {
+		page.Messages = messages[:limit]
+		page.HasMore = true
+	}
+
+	if page.Messages == nil {
+		page.Messages = []*domain.Message{}
+	}
+
+	if page.HasMore {
+		cursor := page.Messages[len(page.Messages)-1].ID
+		page.NextCursor = &cursor
+	}
+
+	return page, nil
+}
+
+// MarkRead marks messages received by the user in the match as read up to the given message
+func (uc *MessageUseCase) MarkRead(ctx context.Context, userID, matchID int, req *MarkReadRequest) (int, error) {
+	m, err := uc.getParticipantMatch(ctx, userID, matchID)
+	if err != nil {
+		return 0, err
+	}
+
+	message, err := uc.messageRepo.GetByID(ctx, req.UpToMessageID)
+	if err != nil {
+		return 0, err
+	}
+	if message.MatchID != matchID {
+		return 0, domain.ErrMessageNotFound
+	}
+
+	count, err := uc.messageRepo.MarkReadUpTo(ctx, matchID, userID, req.UpToMessageID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark messages as read: %w", err)
+	}
+
+	if count > 0 {
+		otherUserID, _ := m.GetOtherUserID(userID)
+		uc.publisher.Publish(ctx, realtime.EventMessagesRead, realtime.MessagesReadPayload{
+			MatchID:       matchID,
+			ReaderID:      userID,
+			UpToMessageID: req.UpToMessageID,
+		}, otherUserID)
+	}
+
+	return count, nil
+}
+
+// SendTyping forwards a typing indicator to the other user of the match
+func (uc *MessageUseCase) SendTyping(ctx context.Context, userID, matchID int) error {
+	m, err := uc.getParticipantMatch(ctx, userID, matchID)
+	if err != nil {
+		return err
+	}
+
+	otherUserID, _ := m.GetOtherUserID(userID)
+	uc.publisher.Publish(ctx, realtime.EventTyping, realtime.TypingPayload{
+		MatchID: matchID,
+		UserID:  userID,
+	}, otherUserID)
+
+	return nil
+}
+
+// GetUnreadCount returns the number of unread messages across all matches
+func (uc *MessageUseCase) GetUnreadCount(ctx context.Context, userID int) (int, error) {
+	count, err := uc.messageRepo.GetUnreadCount(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get unread count: %w", err)
+	}
+	return count, nil
+}
+
+// getParticipantMatch loads the match and checks that the user can chat in it
+func (uc *MessageUseCase) getParticipantMatch(ctx context.Context, userID, matchID int) (*domain.Match, error) {
+	m, err := uc.matchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.HasUser(userID) {
+		return nil, domain.ErrUnauthorizedMessage
+	}
+
+	if !m.IsActive {
+		return nil, domain.ErrNotMatched
+	}
+
+	return m, nil
+}
@@ -0,0 +1,103 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+)
+
+type NotificationUseCase struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationUseCase(notificationRepo repository.NotificationRepository) *NotificationUseCase {
+	return &NotificationUseCase{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// NotificationsPage represents a page of notifications, newest first
+type NotificationsPage struct {
+	Notifications []*domain.Notification `json:"notifications"`
+	Total         int                    `json:"total"`
+	UnreadCount   int                    `json:"unread_count"`
+}
+
+// GetNotifications returns notifications of the user
+func (uc *NotificationUseCase) GetNotifications(ctx context.Context, userID int, unreadOnly bool, limit, offset int) (*NotificationsPage, error) {
+	notifications, err := uc.notificationRepo.GetUserNotifications(ctx, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	if notifications == nil {
+		notifications = []*domain.Notification{}
+	}
+
+	total, err := uc.notificationRepo.CountUserNotifications(ctx, userID, unreadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	unread, err := uc.notificationRepo.GetUnreadCount(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unread count: %w", err)
+	}
+
+	return &NotificationsPage{
+		Notifications: notifications,
+		Total:         total,
+		UnreadCount:   unread,
+	}, nil
+}
+
+// MarkAsRead marks a notification of the user as read
+func (uc *NotificationUseCase) MarkAsRead(ctx context.Context, userID, notificationID int) error {
+	if _, err := uc.getOwnNotification(ctx, userID, notificationID); err != nil {
+		return err
+	}
+
+	return uc.notificationRepo.MarkAsRead(ctx, notificationID)
+}
+
+// MarkAllAsRead marks all notifications of the user as read and returns their number
+func (uc *NotificationUseCase) MarkAllAsRead(ctx context.Context, userID int) (int, error) {
+	count, err := uc.notificationRepo.MarkAllAsRead(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return count, nil
+}
+
+// GetUnreadCount returns the number of unread notifications
+func (uc *NotificationUseCase) GetUnreadCount(ctx context.Context, userID int) (int, error) {
+	count, err := uc.notificationRepo.GetUnreadCount(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get unread count: %w", err)
+	}
+	return count, nil
+}
+
+// Delete deletes a notification of the user
+func (uc *NotificationUseCase) Delete(ctx context.Context, userID, notificationID int) error {
+	if _, err := uc.getOwnNotification(ctx, userID, notificationID); err != nil {
+		return err
+	}
+
+	return uc.notificationRepo.Delete(ctx, notificationID)
+}
+
+// getOwnNotification loads a notification and hides notifications of other users
+func (uc *NotificationUseCase) getOwnNotification(ctx context.Context, userID, notificationID int) (*domain.Notification, error) {
+	notification, err := uc.notificationRepo.GetByID(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if notification.UserID != userID {
+		return nil, domain.ErrNotificationNotFound
+	}
+
+	return notification, nil
+}
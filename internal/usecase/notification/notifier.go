@@ -0,0 +1,231 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/realtime"
+	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+)
+
+const (
+	// notifierQueueSize is the number of pending notifications buffered in memory
+	notifierQueueSize = 1024
+	// notifierWriteTimeout limits the time spent on creating a single notification
+	notifierWriteTimeout = 5 * time.Second
+)
+
+// notificationJob is a notification waiting to be created
+type notificationJob struct {
+	userID  int
+	kind    domain.NotificationKind
+	matchID int
+	payload interface{}
+	// actorID is the user whose name is shown in the content
+	actorID int
+}
+
+// Notifier creates notifications in the background so that a failure
+// to write a notification never fails the request that caused it
+type Notifier struct {
+	notificationRepo repository.NotificationRepository
+	profileRepo      repository.ProfileRepository
+	publisher        *realtime.Publisher
+	pusher           *Pusher
+
+	queue  chan notificationJob
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewNotifier creates a new background notifier. Call Start to run the worker.
+// pusher may be nil if VK push notifications are not configured.
+func NewNotifier(
+	notificationRepo repository.NotificationRepository,
+	profileRepo repository.ProfileRepository,
+	publisher *realtime.Publisher,
+	pusher *Pusher,
+) *Notifier {
+	return &Notifier{
+		notificationRepo: notificationRepo,
+		profileRepo:      profileRepo,
+		publisher:        publisher,
+		pusher:           pusher,
+		queue:            make(chan notificationJob, notifierQueueSize),
+	}
+}
+
+// Start runs the worker
+func (n *Notifier) Start() {
+	n.wg.Add(1)
+	go n.run()
+}
+
+// Close stops accepting notifications and waits until the queue is drained
+func (n *Notifier) Close() {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+
+	n.wg.Wait()
+}
+
+// NotifyNewMatch notifies the user about a match with otherUserID
+func (n *Notifier) NotifyNewMatch(userID, otherUserID, matchID int) {
+	n.enqueue(notificationJob{
+		userID:  userID,
+		kind:    domain.NotificationKindNewMatch,
+		matchID: matchID,
+		actorID: otherUserID,
+		payload: domain.NewMatchNotificationPayload{
+			MatchID:     matchID,
+			OtherUserID: otherUserID,
+		},
+	})
+}
+
+// NotifyLikeReceived notifies the user that fromUserID liked them
+func (n *Notifier) NotifyLikeReceived(userID, fromUserID, swipeID int) {
+	n.enqueue(notificationJob{
+		userID:  userID,
+		kind:    domain.NotificationKindLikeReceived,
+		actorID: fromUserID,
+		payload: domain.LikeReceivedNotificationPayload{
+			SwipeID:    swipeID,
+			FromUserID: fromUserID,
+		},
+	})
+}
+
+// NotifySuperLike notifies the user that fromUserID super liked them
+func (n *Notifier) NotifySuperLike(userID, fromUserID, swipeID int) {
+	n.enqueue(notificationJob{
+		userID:  userID,
+		kind:    domain.NotificationKindSuperLike,
+		actorID: fromUserID,
+		payload: domain.LikeReceivedNotificationPayload{
+			SwipeID:    swipeID,
+			FromUserID: fromUserID,
+		},
+	})
+}
+
+// NotifyNewMessage notifies the recipient about a new message
+func (n *Notifier) NotifyNewMessage(recipientID int, message *domain.Message) {
+	n.enqueue(notificationJob{
+		userID:  recipientID,
+		kind:    domain.NotificationKindNewMessage,
+		matchID: message.MatchID,
+		actorID: message.SenderID,
+		payload: domain.NewMessageNotificationPayload{
+			MatchID:   message.MatchID,
+			MessageID: message.ID,
+			SenderID:  message.SenderID,
+		},
+	})
+}
+
+// enqueue adds the job without blocking; notifications are dropped when the queue is full
+func (n *Notifier) enqueue(job notificationJob) {
+	if n == nil {
+		return
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.closed {
+		fmt.Printf("⚠️  [Notifier] Dropped %s notification for user %d: notifier is closed\n", job.kind, job.userID)
+		return
+	}
+
+	select {
+	case n.queue <- job:
+	default:
+		fmt.Printf("⚠️  [Notifier] Queue is full, dropped %s notification for user %d\n", job.kind, job.userID)
+	}
+}
+
+// run processes queued jobs until the queue is closed
+func (n *Notifier) run() {
+	defer n.wg.Done()
+
+	for job := range n.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), notifierWriteTimeout)
+		if err := n.create(ctx, job); err != nil {
+			fmt.Printf("❌ [Notifier] Failed to create %s notification for user %d: %v\n", job.kind, job.userID, err)
+		}
+		cancel()
+	}
+}
+
+// create stores the notification and pushes it to the user's real-time channel
+func (n *Notifier) create(ctx context.Context, job notificationJob) error {
+	// One unread chat notification per match is enough
+	if job.kind == domain.NotificationKindNewMessage {
+		exists, err := n.notificationRepo.HasUnreadForMatch(ctx, job.userID, job.kind, job.matchID)
+		if err != nil {
+			return fmt.Errorf("failed to check unread notifications: %w", err)
+		}
+		if exists {
+			return nil
+		}
+	}
+
+	payload, err := domain.NewNotificationPayload(job.payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	notification := &domain.Notification{
+		UserID:  job.userID,
+		Kind:    job.kind,
+		Content: n.content(ctx, job),
+		Payload: payload,
+		IsRead:  false,
+	}
+
+	if err := n.notificationRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	n.publisher.Publish(ctx, realtime.EventNotification, notification, job.userID)
+
+	// Push to the device only for matches, messages and super likes, regular likes stay in-app
+	switch job.kind {
+	case domain.NotificationKindNewMatch, domain.NotificationKindNewMessage:
+		n.pusher.Push(job.userID, notification.Content, fmt.Sprintf("match/%d", job.matchID))
+	case domain.NotificationKindSuperLike:
+		n.pusher.Push(job.userID, notification.Content, "likes")
+	}
+
+	return nil
+}
+
+// content builds the human readable notification text
+func (n *Notifier) content(ctx context.Context, job notificationJob) string {
+	name := "Кто-то"
+	if profile, err := n.profileRepo.GetByUserID(ctx, job.actorID); err == nil {
+		name = profile.DisplayName
+	}
+
+	switch job.kind {
+	case domain.NotificationKindNewMatch:
+		return fmt.Sprintf("У вас новое совпадение с %s!", name)
+	case domain.NotificationKindLikeReceived:
+		return fmt.Sprintf("%s поставил(а) вам лайк", name)
+	case domain.NotificationKindSuperLike:
+		return fmt.Sprintf("%s отправил(а) вам суперлайк!", name)
+	case domain.NotificationKindNewMessage:
+		return fmt.Sprintf("Новое сообщение от %s", name)
+	}
+
+	return "Новое уведомление"
+}
@@ -0,0 +1,153 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+	"github.com/gdugdh24/mpit2026-backend/pkg/vkapi"
+)
+
+const (
+	// pusherQueueSize is the number of pending push notifications buffered in memory
+	pusherQueueSize = 1024
+	// pushFlushInterval is how long pushes are collected before sending,
+	// identical pushes in one flush are sent with a single VK API call
+	pushFlushInterval = 2 * time.Second
+	// pushSendTimeout limits the time spent on sending a single flush
+	pushSendTimeout = 30 * time.Second
+)
+
+// pushJob is a push notification waiting to be sent
+type pushJob struct {
+	userID   int
+	message  string
+	fragment string
+}
+
+// pushKey groups jobs that can be sent with one notifications.sendMessage call
+type pushKey struct {
+	message  string
+	fragment string
+}
+
+// Pusher delivers VK Mini App push notifications in the background
+// to users who allowed them
+type Pusher struct {
+	vkClient     *vkapi.Client
+	userRepo     repository.UserRepository
+	serviceToken string
+
+	queue  chan pushJob
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewPusher creates a new push delivery worker. Call Start to run it.
+func NewPusher(vkClient *vkapi.Client, userRepo repository.UserRepository, serviceToken string) *Pusher {
+	return &Pusher{
+		vkClient:     vkClient,
+		userRepo:     userRepo,
+		serviceToken: serviceToken,
+		queue:        make(chan pushJob, pusherQueueSize),
+	}
+}
+
+// Start runs the worker
+func (p *Pusher) Start() {
+	p.wg.Add(1)
+	go p.run()
+}
+
+// Close stops accepting pushes and waits until the queue is sent
+func (p *Pusher) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// Push queues a push notification to the user without blocking.
+// fragment is the Mini App URL hash opened when the user taps the notification.
+func (p *Pusher) Push(userID int, message, fragment string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return
+	}
+
+	select {
+	case p.queue <- pushJob{userID: userID, message: message, fragment: fragment}:
+	default:
+		fmt.Printf("⚠️  [Push] Queue is full, dropped push for user %d\n", userID)
+	}
+}
+
+// run collects queued jobs and sends them every flush interval
+func (p *Pusher) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(pushFlushInterval)
+	defer ticker.Stop()
+
+	pending := make(map[pushKey][]int)
+	for {
+		select {
+		case job, ok := <-p.queue:
+			if !ok {
+				p.flush(pending)
+				return
+			}
+			key := pushKey{message: job.message, fragment: job.fragment}
+			pending[key] = append(pending[key], job.userID)
+		case <-ticker.C:
+			if len(pending) > 0 {
+				p.flush(pending)
+				pending = make(map[pushKey][]int)
+			}
+		}
+	}
+}
+
+// flush sends collected pushes grouped by message
+func (p *Pusher) flush(pending map[pushKey][]int) {
+	ctx, cancel := context.WithTimeout(context.Background(), pushSendTimeout)
+	defer cancel()
+
+	for key, userIDs := range pending {
+		// Only users who allowed notifications in the Mini App
+		vkIDs, err := p.userRepo.GetNotifiableVKIDs(ctx, userIDs)
+		if err != nil {
+			fmt.Printf("❌ [Push] Failed to get recipients: %v\n", err)
+			continue
+		}
+		if len(vkIDs) == 0 {
+			continue
+		}
+
+		results, err := p.vkClient.SendNotification(ctx, p.serviceToken, vkIDs, key.message, key.fragment)
+		if err != nil {
+			fmt.Printf("❌ [Push] Failed to send push to %d users: %v\n", len(vkIDs), err)
+			continue
+		}
+
+		for _, result := range results {
+			if !result.Status && result.Error != nil {
+				fmt.Printf("⚠️  [Push] Push to VK user %d was not delivered: %d %s\n",
+					result.UserID, result.Error.Code, result.Error.Description)
+			}
+		}
+	}
+}
@@ -5,21 +5,32 @@ import (
 	"fmt"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/ai"
 	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/feed"
 )
 
 type ProfileUseCase struct {
 	profileRepo repository.ProfileRepository
 	userRepo    repository.UserRepository
+	bigFiveRepo repository.BigFiveRepository
+	decks       *feed.DeckCache
+	wingman     ai.Wingman
 }
 
 func NewProfileUseCase(
 	profileRepo repository.ProfileRepository,
 	userRepo repository.UserRepository,
+	bigFiveRepo repository.BigFiveRepository,
+	decks *feed.DeckCache,
+	wingman ai.Wingman,
 ) *ProfileUseCase {
 	return &ProfileUseCase{
 		profileRepo: profileRepo,
 		userRepo:    userRepo,
+		bigFiveRepo: bigFiveRepo,
+		decks:       decks,
+		wingman:     wingman,
 	}
 }
 
@@ -32,19 +43,22 @@ type CreateProfileRequest struct {
 	PrefMinAge        *int     `json:"pref_min_age" binding:"omitempty,min=18,max=100"`
 	PrefMaxAge        *int     `json:"pref_max_age" binding:"omitempty,min=18,max=100"`
 	PrefMaxDistanceKm *int     `json:"pref_max_distance_km" binding:"omitempty,min=1,max=1000"`
+	// InterestedIn defaults to the opposite gender
+	InterestedIn []domain.Gender `json:"interested_in" binding:"omitempty,min=1,max=2,dive,oneof=male female"`
 }
 
 // UpdateProfileRequest represents profile update request
 type UpdateProfileRequest struct {
-	DisplayName       *string   `json:"display_name" binding:"omitempty,min=2,max=100"`
-	Bio               *string   `json:"bio" binding:"omitempty,max=500"`
-	City              *string   `json:"city" binding:"omitempty,max=100"`
-	Interests         *[]string `json:"interests" binding:"omitempty,max=10"`
-	LocationLat       *float64  `json:"location_lat" binding:"omitempty,min=-90,max=90"`
-	LocationLon       *float64  `json:"location_lon" binding:"omitempty,min=-180,max=180"`
-	PrefMinAge        *int      `json:"pref_min_age" binding:"omitempty,min=18,max=100"`
-	PrefMaxAge        *int      `json:"pref_max_age" binding:"omitempty,min=18,max=100"`
-	PrefMaxDistanceKm *int      `json:"pref_max_distance_km" binding:"omitempty,min=1,max=1000"`
+	DisplayName       *string          `json:"display_name" binding:"omitempty,min=2,max=100"`
+	Bio               *string          `json:"bio" binding:"omitempty,max=500"`
+	City              *string          `json:"city" binding:"omitempty,max=100"`
+	Interests         *[]string        `json:"interests" binding:"omitempty,max=10"`
+	LocationLat       *float64         `json:"location_lat" binding:"omitempty,min=-90,max=90"`
+	LocationLon       *float64         `json:"location_lon" binding:"omitempty,min=-180,max=180"`
+	PrefMinAge        *int             `json:"pref_min_age" binding:"omitempty,min=18,max=100"`
+	PrefMaxAge        *int             `json:"pref_max_age" binding:"omitempty,min=18,max=100"`
+	PrefMaxDistanceKm *int             `json:"pref_max_distance_km" binding:"omitempty,min=1,max=1000"`
+	InterestedIn      *[]domain.Gender `json:"interested_in" binding:"omitempty,min=1,max=2,dive,oneof=male female"`
 }
 
 // ProfileResponse represents profile response with additional info
@@ -63,6 +77,33 @@ func (uc *ProfileUseCase) GetMyProfile(ctx context.Context, userID int) (*domain
 	return profile, nil
 }
 
+// BioSuggestionsResponse represents AI Wingman bio variants
+type BioSuggestionsResponse struct {
+	Suggestions []string `json:"suggestions"`
+}
+
+// SuggestBio generates bio variants for the user's profile with the AI Wingman
+func (uc *ProfileUseCase) SuggestBio(ctx context.Context, userID int) (*BioSuggestionsResponse, error) {
+	profile, err := uc.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var traits *domain.Traits
+	if result, err := uc.bigFiveRepo.GetByUserID(ctx, userID); err == nil {
+		traits = result.Traits()
+	}
+
+	suggestions, err := uc.wingman.BioSuggestions(ctx, uc.wingman.PromptVersion(userID), ai.NewPerson(profile, traits))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate bio suggestions: %w", err)
+	}
+
+	return &BioSuggestionsResponse{
+		Suggestions: suggestions,
+	}, nil
+}
+
 // GetProfileByUserID returns profile by user ID with calculated age and distance
 func (uc *ProfileUseCase) GetProfileByUserID(ctx context.Context, targetUserID int, currentUserID *int) (*ProfileResponse, error) {
 	profile, err := uc.profileRepo.GetByUserID(ctx, targetUserID)
@@ -104,6 +145,19 @@ func (uc *ProfileUseCase) CreateProfile(ctx context.Context, userID int, req *Cr
 		return nil, domain.ErrProfileAlreadyExists
 	}
 
+	interestedIn := req.InterestedIn
+	if len(interestedIn) == 0 {
+		user, err := uc.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		interestedIn = domain.DefaultInterestedIn(user.Gender)
+	}
+	interestedIn, err = normalizeInterestedIn(interestedIn)
+	if err != nil {
+		return nil, err
+	}
+
 	profile := &domain.Profile{
 		UserID:               userID,
 		DisplayName:          req.DisplayName,
@@ -113,6 +167,7 @@ func (uc *ProfileUseCase) CreateProfile(ctx context.Context, userID int, req *Cr
 		PrefMinAge:           req.PrefMinAge,
 		PrefMaxAge:           req.PrefMaxAge,
 		PrefMaxDistanceKm:    req.PrefMaxDistanceKm,
+		InterestedIn:         interestedIn,
 		IsOnboardingComplete: true,
 	}
 
@@ -158,14 +213,44 @@ func (uc *ProfileUseCase) UpdateProfile(ctx context.Context, userID int, req *Up
 	if req.PrefMaxDistanceKm != nil {
 		profile.PrefMaxDistanceKm = req.PrefMaxDistanceKm
 	}
+	if req.InterestedIn != nil {
+		interestedIn, err := normalizeInterestedIn(*req.InterestedIn)
+		if err != nil {
+			return nil, err
+		}
+		profile.InterestedIn = interestedIn
+	}
 
 	if err := uc.profileRepo.Update(ctx, profile); err != nil {
 		return nil, fmt.Errorf("failed to update profile: %w", err)
 	}
 
+	// Preferences and location change the feed ranking
+	uc.decks.Invalidate(ctx, userID)
+
 	return profile, nil
 }
 
+// normalizeInterestedIn validates the gender preference and removes duplicates
+func normalizeInterestedIn(genders []domain.Gender) ([]domain.Gender, error) {
+	result := make([]domain.Gender, 0, len(genders))
+	seen := make(map[domain.Gender]bool, len(genders))
+	for _, g := range genders {
+		if !g.IsValid() {
+			return nil, domain.ErrInvalidInterestedIn
+		}
+		if !seen[g] {
+			seen[g] = true
+			result = append(result, g)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, domain.ErrInvalidInterestedIn
+	}
+	return result, nil
+}
+
 // calculateDistance calculates distance between two points using Haversine formula
 func calculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
 	const earthRadius = 6371 // km
@@ -0,0 +1,119 @@
+package swipe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/jobs"
+)
+
+// Background job kinds queued by swipes
+const (
+	JobLearnPreferences  = "swipe.learn_preferences"
+	JobEnrichMatch       = "match.enrich"
+	JobRequeueSuperLiker = "feed.requeue_super_liker"
+)
+
+// learnPreferencesPayload is the payload of a JobLearnPreferences job
+type learnPreferencesPayload struct {
+	SwipeID  int `json:"swipe_id"`
+	SwiperID int `json:"swiper_id"`
+	SwipedID int `json:"swiped_id"`
+}
+
+// enrichMatchPayload is the payload of a JobEnrichMatch job
+type enrichMatchPayload struct {
+	MatchID int `json:"match_id"`
+}
+
+// requeuePayload is the payload of a JobRequeueSuperLiker job
+type requeuePayload struct {
+	UserID      int `json:"user_id"`
+	CandidateID int `json:"candidate_id"`
+}
+
+// RegisterJobs sets the handlers of jobs queued by swipes
+func (uc *SwipeUseCase) RegisterJobs(q *jobs.Queue) {
+	q.Register(JobLearnPreferences, uc.handleLearnPreferences)
+	q.Register(JobEnrichMatch, uc.handleEnrichMatch)
+	q.Register(JobRequeueSuperLiker, uc.handleRequeueSuperLiker)
+}
+
+// handleLearnPreferences shifts the swiper's preferences towards the liked user.
+// The previous ideal is saved with the swipe so the update can be undone.
+func (uc *SwipeUseCase) handleLearnPreferences(ctx context.Context, data json.RawMessage) error {
+	var payload learnPreferencesPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid payload: %w", err))
+	}
+
+	return uc.uow.Do(ctx, func(ctx context.Context) error {
+		// The lock keeps concurrent likes of the user from overwriting each other's learning
+		profile, err := uc.profileRepo.GetByUserIDForUpdate(ctx, payload.SwiperID)
+		if err != nil {
+			if errors.Is(err, domain.ErrProfileNotFound) {
+				return nil
+			}
+			return fmt.Errorf("failed to get profile: %w", err)
+		}
+
+		before, err := uc.learnPreferences(ctx, profile, payload.SwipedID)
+		if err != nil {
+			return fmt.Errorf("failed to learn preferences: %w", err)
+		}
+		if before == nil {
+			return nil
+		}
+
+		if err := uc.swipeRepo.SetIdealBefore(ctx, payload.SwipeID, before); err != nil {
+			if errors.Is(err, domain.ErrSwipeNotFound) {
+				// The like was undone before the job ran
+				return nil
+			}
+			return fmt.Errorf("failed to save ideal before swipe: %w", err)
+		}
+
+		if err := uc.profileRepo.Update(ctx, profile); err != nil {
+			return fmt.Errorf("failed to update preferences: %w", err)
+		}
+		return nil
+	})
+}
+
+// handleEnrichMatch generates AI Wingman content of a new match
+func (uc *SwipeUseCase) handleEnrichMatch(ctx context.Context, data json.RawMessage) error {
+	var payload enrichMatchPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid payload: %w", err))
+	}
+
+	match, err := uc.matchRepo.GetByID(ctx, payload.MatchID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMatchNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get match: %w", err)
+	}
+	if !match.IsActive {
+		return nil
+	}
+
+	return uc.enrichMatchWithAI(ctx, match)
+}
+
+// handleRequeueSuperLiker puts the super liker at the top of the recipient's deck.
+// Re-ranking the deck is too slow for the swiper's request, so it runs here.
+func (uc *SwipeUseCase) handleRequeueSuperLiker(ctx context.Context, data json.RawMessage) error {
+	var payload requeuePayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid payload: %w", err))
+	}
+
+	if err := uc.feedUseCase.Requeue(ctx, payload.UserID, payload.CandidateID); err != nil {
+		return fmt.Errorf("failed to requeue user %d in deck of user %d: %w", payload.CandidateID, payload.UserID, err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package swipe
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+)
+
+// massLikerCheckTimeout limits the time spent on a single check
+const massLikerCheckTimeout = 5 * time.Minute
+
+// MassLikerConfig describes who is considered a mass liker
+type MassLikerConfig struct {
+	// Window is how far back swipes are counted
+	Window time.Duration
+	// MinSwipes is the number of swipes in the window below which nobody is flagged
+	MinSwipes int
+	// MinLikeRatio is the share of likes (0..1) from which the user is flagged
+	MinLikeRatio float64
+	// Interval is how often flags are recalculated
+	Interval time.Duration
+}
+
+// MassLikerDetector periodically flags users who like almost everyone they swipe,
+// the feed demotes flagged users
+type MassLikerDetector struct {
+	swipeRepo repository.SwipeRepository
+	cfg       MassLikerConfig
+
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// NewMassLikerDetector creates a new detector. Call Start to run it.
+func NewMassLikerDetector(swipeRepo repository.SwipeRepository, cfg MassLikerConfig) *MassLikerDetector {
+	return &MassLikerDetector{
+		swipeRepo: swipeRepo,
+		cfg:       cfg,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start runs the detection loop, the first check is done immediately
+func (d *MassLikerDetector) Start() {
+	d.wg.Add(1)
+	go d.run()
+}
+
+// Close stops the detection loop and waits for the running check
+func (d *MassLikerDetector) Close() {
+	d.once.Do(func() {
+		close(d.stop)
+	})
+	d.wg.Wait()
+}
+
+func (d *MassLikerDetector) run() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		d.check()
+
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check recalculates mass liker flags once
+func (d *MassLikerDetector) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), massLikerCheckTimeout)
+	defer cancel()
+
+	since := time.Now().Add(-d.cfg.Window)
+	count, err := d.swipeRepo.FlagMassLikers(ctx, since, d.cfg.MinSwipes, d.cfg.MinLikeRatio)
+	if err != nil {
+		fmt.Printf("❌ [Swipe] Failed to flag mass likers: %v\n", err)
+		return
+	}
+	if count > 0 {
+		fmt.Printf("✅ [Swipe] Mass liker flag changed for %d users\n", count)
+	}
+}
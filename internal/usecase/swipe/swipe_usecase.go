@@ -2,19 +2,48 @@ package swipe
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gdugdh24/mpit2026-backend/internal/domain"
-	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/gemini"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/ai"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/jobs"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/ratelimit"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/realtime"
 	"github.com/gdugdh24/mpit2026-backend/internal/repository"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/feed"
+	"github.com/gdugdh24/mpit2026-backend/internal/usecase/notification"
 )
 
+// UndoConfig limits swipe rewinds
+type UndoConfig struct {
+	// Window is how long after a swipe it can be undone
+	Window time.Duration
+	// DailyLimit is the max number of rewinds per user in 24 hours
+	DailyLimit int
+}
+
 type SwipeUseCase struct {
-	swipeRepo    repository.SwipeRepository
-	matchRepo    repository.MatchRepository
-	profileRepo  repository.ProfileRepository
-	userRepo     repository.UserRepository
-	geminiClient *gemini.GeminiClient
+	swipeRepo   repository.SwipeRepository
+	matchRepo   repository.MatchRepository
+	profileRepo repository.ProfileRepository
+	userRepo    repository.UserRepository
+	bigFiveRepo repository.BigFiveRepository
+	uow         repository.UnitOfWork
+	jobs        *jobs.Queue
+	wingman     ai.Wingman
+	publisher   *realtime.Publisher
+	notifier    *notification.Notifier
+	feedUseCase *feed.FeedUseCase
+	undo        UndoConfig
+	// superLikeDailyLimit is the max number of super likes per user in 24 hours
+	superLikeDailyLimit int
+	// swipeLimiter limits swipes per minute, likeLimiter limits likes per day
+	swipeLimiter *ratelimit.Limiter
+	likeLimiter  *ratelimit.Limiter
 }
 
 func NewSwipeUseCase(
@@ -22,14 +51,34 @@ func NewSwipeUseCase(
 	matchRepo repository.MatchRepository,
 	profileRepo repository.ProfileRepository,
 	userRepo repository.UserRepository,
-	geminiClient *gemini.GeminiClient,
+	bigFiveRepo repository.BigFiveRepository,
+	uow repository.UnitOfWork,
+	jobQueue *jobs.Queue,
+	wingman ai.Wingman,
+	publisher *realtime.Publisher,
+	notifier *notification.Notifier,
+	feedUseCase *feed.FeedUseCase,
+	undo UndoConfig,
+	superLikeDailyLimit int,
+	swipeLimiter *ratelimit.Limiter,
+	likeLimiter *ratelimit.Limiter,
 ) *SwipeUseCase {
 	return &SwipeUseCase{
-		swipeRepo:    swipeRepo,
-		matchRepo:    matchRepo,
-		profileRepo:  profileRepo,
-		userRepo:     userRepo,
-		geminiClient: geminiClient,
+		swipeRepo:           swipeRepo,
+		matchRepo:           matchRepo,
+		profileRepo:         profileRepo,
+		userRepo:            userRepo,
+		bigFiveRepo:         bigFiveRepo,
+		uow:                 uow,
+		jobs:                jobQueue,
+		wingman:             wingman,
+		publisher:           publisher,
+		notifier:            notifier,
+		feedUseCase:         feedUseCase,
+		undo:                undo,
+		superLikeDailyLimit: superLikeDailyLimit,
+		swipeLimiter:        swipeLimiter,
+		likeLimiter:         likeLimiter,
 	}
 }
 
@@ -37,6 +86,19 @@ func NewSwipeUseCase(
 type SwipeRequest struct {
 	SwipedUserID int  `json:"swiped_user_id" binding:"required"`
 	IsLike       bool `json:"is_like"`
+	// Kind overrides IsLike, required for super likes
+	Kind domain.SwipeKind `json:"kind" binding:"omitempty,oneof=like dislike super_like"`
+}
+
+// kind returns the requested swipe kind, falling back to IsLike
+func (r *SwipeRequest) kind() domain.SwipeKind {
+	if r.Kind != "" {
+		return r.Kind
+	}
+	if r.IsLike {
+		return domain.SwipeKindLike
+	}
+	return domain.SwipeKindDislike
 }
 
 // SwipeResponse represents swipe result
@@ -45,6 +107,22 @@ type SwipeResponse struct {
 	Swipe       *domain.Swipe       `json:"swipe,omitempty"`
 	Match       *domain.Match       `json:"match,omitempty"`
 	MatchedUser *MatchedUserProfile `json:"matched_user,omitempty"`
+	// Limits is sent in response headers
+	Limits *SwipeLimits `json:"-"`
+}
+
+// SwipeLimits is what is left of the swipe rate limit and the like quota, -1 if not limited
+type SwipeLimits struct {
+	SwipesRemaining int
+	LikesRemaining  int
+}
+
+// UndoResponse represents the undone swipe
+type UndoResponse struct {
+	Swipe *domain.Swipe `json:"swipe"`
+	// MatchRemoved is true if the undone like had created a match that was deactivated
+	MatchRemoved bool `json:"match_removed"`
+	RewindsLeft  int  `json:"rewinds_left"`
 }
 
 // MatchedUserProfile represents matched user info
@@ -59,9 +137,10 @@ type MatchedUserProfile struct {
 
 // LikeReceivedResponse represents a like received
 type LikeReceivedResponse struct {
-	SwipeID   int                 `json:"swipe_id"`
-	User      *MatchedUserProfile `json:"user"`
-	CreatedAt string              `json:"created_at"`
+	SwipeID     int                 `json:"swipe_id"`
+	IsSuperLike bool                `json:"is_super_like"`
+	User        *MatchedUserProfile `json:"user"`
+	CreatedAt   string              `json:"created_at"`
 }
 
 // CreateSwipe creates a new swipe and checks for match
@@ -71,6 +150,22 @@ func (uc *SwipeUseCase) CreateSwipe(ctx context.Context, swiperID int, req *Swip
 		return nil, domain.ErrCannotSwipeSelf
 	}
 
+	kind := req.kind()
+
+	// Limits go first so scripts can't hammer the database
+	limits, charge, err := uc.checkLimits(ctx, swiperID, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only saved swipes are paid for
+	saved := false
+	defer func() {
+		if !saved {
+			uc.refundLimits(context.WithoutCancel(ctx), swiperID, charge)
+		}
+	}()
+
 	// Check if already swiped
 	existingSwipe, err := uc.swipeRepo.GetByUsers(ctx, swiperID, req.SwipedUserID)
 	if err == nil && existingSwipe != nil {
@@ -81,87 +176,212 @@ func (uc *SwipeUseCase) CreateSwipe(ctx context.Context, swiperID int, req *Swip
 	swipe := &domain.Swipe{
 		SwiperID: swiperID,
 		SwipedID: req.SwipedUserID,
-		IsLike:   req.IsLike,
+		Kind:     kind,
 	}
 
-	if err := uc.swipeRepo.Create(ctx, swipe); err != nil {
-		return nil, fmt.Errorf("failed to create swipe: %w", err)
+	// Swipe, mutual check and match are written atomically, see createSwipe
+	match, err := uc.createSwipe(ctx, swipe)
+	if err != nil {
+		return nil, err
 	}
+	saved = true
 
 	response := &SwipeResponse{
 		IsMatch: false,
 		Swipe:   swipe,
+		Limits:  limits,
 	}
 
-	// If it's a like, check for mutual like (match)
-	if req.IsLike {
-		// 1. Reinforcement Learning: Update user's preferences
-		// We do this asynchronously to not block the response
-		go uc.updateUserPreferences(ctx, swiperID, req.SwipedUserID)
+	if !swipe.IsLike {
+		return response, nil
+	}
 
-		isMutual, err := uc.swipeRepo.CheckMutualLike(ctx, swiperID, req.SwipedUserID)
-		if err != nil {
-			fmt.Printf("❌ [Match] CheckMutualLike failed: %v\n", err)
-			return response, nil // Return swipe even if match check fails
+	if match == nil {
+		// Let the swiped user know someone liked them
+		uc.publisher.Publish(ctx, realtime.EventLikeReceived, realtime.LikeReceivedPayload{
+			SwipeID:     swipe.ID,
+			FromUserID:  swiperID,
+			IsSuperLike: kind == domain.SwipeKindSuperLike,
+		}, req.SwipedUserID)
+
+		if kind == domain.SwipeKindSuperLike {
+			uc.notifier.NotifySuperLike(req.SwipedUserID, swiperID, swipe.ID)
+		} else {
+			uc.notifier.NotifyLikeReceived(req.SwipedUserID, swiperID, swipe.ID)
 		}
 
-		fmt.Printf("🔍 [Match] CheckMutualLike result: %v (swiper=%d, swiped=%d)\n", isMutual, swiperID, req.SwipedUserID)
+		return response, nil
+	}
 
-		if isMutual {
-			fmt.Printf("💕 [Match] Mutual like detected! Creating match...\n")
-			// Create match
-			match, err := uc.createMatch(ctx, swiperID, req.SwipedUserID)
-			if err != nil {
-				fmt.Printf("❌ [Match] createMatch failed: %v\n", err)
-				return response, nil // Return swipe even if match creation fails
-			}
-			fmt.Printf("✅ [Match] Match created: ID=%d\n", match.ID)
-
-			// Get matched user profile
-			matchedUser, err := uc.getMatchedUserProfile(ctx, req.SwipedUserID)
-			if err == nil {
-				fmt.Printf("✅ [Match] Got matched user profile: %s\n", matchedUser.DisplayName)
-				response.IsMatch = true
-				response.Match = match
-				response.MatchedUser = matchedUser
-
-				// 2. AI Wingman: Generate explanation and icebreakers
-				// Call synchronously for debugging (normally would be async)
-				if uc.geminiClient != nil {
-					fmt.Printf("✅ [AI Wingman] geminiClient is available, calling enrichMatchWithAI...\n")
-					uc.enrichMatchWithAI(ctx, match.ID, swiperID, req.SwipedUserID)
-				} else {
-					fmt.Printf("❌ [AI Wingman] geminiClient is nil, skipping AI enrichment\n")
-				}
-			} else {
-				fmt.Printf("❌ [Match] getMatchedUserProfile failed: %v\n", err)
-			}
-		}
+	fmt.Printf("✅ [Match] Match created: ID=%d\n", match.ID)
+
+	// Notify both users in real time
+	uc.publisher.Publish(ctx, realtime.EventNewMatch, realtime.NewMatchPayload{
+		MatchID:     match.ID,
+		OtherUserID: req.SwipedUserID,
+	}, swiperID)
+	uc.publisher.Publish(ctx, realtime.EventNewMatch, realtime.NewMatchPayload{
+		MatchID:     match.ID,
+		OtherUserID: swiperID,
+	}, req.SwipedUserID)
+	uc.notifier.NotifyNewMatch(swiperID, req.SwipedUserID, match.ID)
+	uc.notifier.NotifyNewMatch(req.SwipedUserID, swiperID, match.ID)
+
+	// Get matched user profile
+	matchedUser, err := uc.getMatchedUserProfile(ctx, req.SwipedUserID)
+	if err == nil {
+		fmt.Printf("✅ [Match] Got matched user profile: %s\n", matchedUser.DisplayName)
+		response.IsMatch = true
+		response.Match = match
+		response.MatchedUser = matchedUser
+	} else {
+		fmt.Printf("❌ [Match] getMatchedUserProfile failed: %v\n", err)
 	}
 
 	return response, nil
 }
 
-// createMatch creates a match between two users
-func (uc *SwipeUseCase) createMatch(ctx context.Context, user1ID, user2ID int) (*domain.Match, error) {
-	// Ensure user1_id < user2_id for database constraint
-	if user1ID > user2ID {
-		user1ID, user2ID = user2ID, user1ID
+// limitCharge holds the attempts counted by checkLimits, nil results were not counted
+type limitCharge struct {
+	rate  *ratelimit.Result
+	quota *ratelimit.Result
+}
+
+// checkLimits counts the swipe against the per-minute rate limit and, for likes, the daily like quota.
+// The returned charge must be refunded if the swipe isn't saved.
+// Limits fail open: if the counters are unavailable the swipe is allowed.
+func (uc *SwipeUseCase) checkLimits(ctx context.Context, swiperID int, kind domain.SwipeKind) (*SwipeLimits, *limitCharge, error) {
+	limits := &SwipeLimits{SwipesRemaining: -1, LikesRemaining: -1}
+	charge := &limitCharge{}
+	key := strconv.Itoa(swiperID)
+
+	rate, err := uc.swipeLimiter.Allow(ctx, key)
+	if err != nil {
+		fmt.Printf("⚠️  [Swipe] Failed to check swipe rate of user %d: %v\n", swiperID, err)
+	} else {
+		if !rate.Allowed {
+			return nil, nil, &domain.LimitError{Err: domain.ErrSwipeRateLimited, Limit: rate.Limit, RetryAfter: rate.RetryAfter}
+		}
+		charge.rate = rate
+		limits.SwipesRemaining = rate.Remaining
+	}
+
+	if !kind.IsLike() {
+		return limits, charge, nil
 	}
 
-	// Check if match already exists
-	existingMatch, err := uc.matchRepo.GetByUsers(ctx, user1ID, user2ID)
-	if err == nil && existingMatch != nil {
-		return existingMatch, nil
+	quota, err := uc.likeLimiter.Allow(ctx, key)
+	if err != nil {
+		fmt.Printf("⚠️  [Swipe] Failed to check like quota of user %d: %v\n", swiperID, err)
+	} else {
+		if !quota.Allowed {
+			// The rejected like doesn't count against the rate either
+			uc.refundLimits(ctx, swiperID, charge)
+			return nil, nil, &domain.LimitError{Err: domain.ErrLikeQuotaExceeded, Limit: quota.Limit, RetryAfter: quota.RetryAfter}
+		}
+		charge.quota = quota
+		limits.LikesRemaining = quota.Remaining
 	}
 
-	match := &domain.Match{
-		User1ID:  user1ID,
-		User2ID:  user2ID,
-		IsActive: true,
+	return limits, charge, nil
+}
+
+// refundLimits gives back the attempts of a swipe that wasn't saved
+func (uc *SwipeUseCase) refundLimits(ctx context.Context, swiperID int, charge *limitCharge) {
+	if err := uc.swipeLimiter.Refund(ctx, charge.rate); err != nil {
+		fmt.Printf("⚠️  [Swipe] Failed to refund swipe rate of user %d: %v\n", swiperID, err)
 	}
+	if err := uc.likeLimiter.Refund(ctx, charge.quota); err != nil {
+		fmt.Printf("⚠️  [Swipe] Failed to refund like quota of user %d: %v\n", swiperID, err)
+	}
+}
+
+// createSwipe saves the swipe and, if it completes a mutual like, creates the match in one transaction.
+// The super like daily limit is checked in the same transaction under the swiper lock.
+// Preference learning, AI enrichment and moving a super liker to the top of the recipient's deck
+// are queued in the same transaction and run in the background.
+// Returns the match if one was created or reactivated, nil otherwise.
+func (uc *SwipeUseCase) createSwipe(ctx context.Context, swipe *domain.Swipe) (*domain.Match, error) {
+	var match *domain.Match
+
+	err := uc.uow.Do(ctx, func(ctx context.Context) error {
+		if swipe.Kind == domain.SwipeKindSuperLike {
+			// Concurrent super likes of the user wait here, so the count can't go stale
+			if err := uc.swipeRepo.LockSwiper(ctx, swipe.SwiperID); err != nil {
+				return fmt.Errorf("failed to lock swiper: %w", err)
+			}
+			sent, err := uc.swipeRepo.CountByKindSince(ctx, swipe.SwiperID, domain.SwipeKindSuperLike, time.Now().Add(-24*time.Hour))
+			if err != nil {
+				return fmt.Errorf("failed to count super likes: %w", err)
+			}
+			if sent >= uc.superLikeDailyLimit {
+				return domain.ErrSuperLikeLimitReached
+			}
+		}
+
+		if swipe.Kind.IsLike() {
+			// Without the lock two users liking each other at the same moment
+			// don't see each other's swipe and neither creates the match
+			if err := uc.swipeRepo.LockPair(ctx, swipe.SwiperID, swipe.SwipedID); err != nil {
+				return fmt.Errorf("failed to lock swipe pair: %w", err)
+			}
+		}
 
-	if err := uc.matchRepo.Create(ctx, match); err != nil {
+		if err := uc.swipeRepo.Create(ctx, swipe); err != nil {
+			if err == domain.ErrSwipeAlreadyExists {
+				return err
+			}
+			return fmt.Errorf("failed to create swipe: %w", err)
+		}
+
+		if !swipe.IsLike {
+			return nil
+		}
+
+		// 1. Reinforcement Learning: a like shifts the user's preferences
+		if err := uc.jobs.Enqueue(ctx, JobLearnPreferences, learnPreferencesPayload{
+			SwipeID:  swipe.ID,
+			SwiperID: swipe.SwiperID,
+			SwipedID: swipe.SwipedID,
+		}); err != nil {
+			return err
+		}
+
+		isMutual, err := uc.swipeRepo.CheckMutualLike(ctx, swipe.SwiperID, swipe.SwipedID)
+		if err != nil {
+			return fmt.Errorf("failed to check mutual like: %w", err)
+		}
+		if !isMutual {
+			if swipe.Kind == domain.SwipeKindSuperLike {
+				// Show the sender first in the recipient's feed
+				return uc.jobs.Enqueue(ctx, JobRequeueSuperLiker, requeuePayload{
+					UserID:      swipe.SwipedID,
+					CandidateID: swipe.SwiperID,
+				})
+			}
+			return nil
+		}
+
+		fmt.Printf("💕 [Match] Mutual like detected! Creating match...\n")
+
+		// A match deactivated by an undone like is restored, an active one is left as is
+		m := &domain.Match{
+			User1ID: swipe.SwiperID,
+			User2ID: swipe.SwipedID,
+		}
+		created, err := uc.matchRepo.Upsert(ctx, m)
+		if err != nil {
+			return fmt.Errorf("failed to create match: %w", err)
+		}
+		if !created {
+			return nil
+		}
+		match = m
+
+		// 2. AI Wingman: generate explanation and icebreakers
+		return uc.jobs.Enqueue(ctx, JobEnrichMatch, enrichMatchPayload{MatchID: m.ID})
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -222,7 +442,8 @@ func (uc *SwipeUseCase) GetLikesReceived(ctx context.Context, userID int, limit,
 		}
 
 		responses = append(responses, &LikeReceivedResponse{
-			SwipeID: like.ID,
+			SwipeID:     like.ID,
+			IsSuperLike: like.Kind == domain.SwipeKindSuperLike,
 			User: &MatchedUserProfile{
 				ID:          profile.ID,
 				DisplayName: profile.DisplayName,
@@ -273,115 +494,116 @@ func asinApprox(x float64) float64 {
 	return x + x*x*x/6 + 3*x*x*x*x*x/40
 }
 
-// updateUserPreferences implements Reinforcement Learning
-// It shifts the user's "Ideal Partner" vector towards the swiped user's traits
-func (uc *SwipeUseCase) updateUserPreferences(ctx context.Context, swiperID, swipedID int) {
-	// Get swiper profile
-	swiperProfile, err := uc.profileRepo.GetByUserID(ctx, swiperID)
+// learnPreferences implements Reinforcement Learning
+// It shifts the "Ideal Partner" vector of the swiper's profile towards the swiped user's traits.
+// Returns the ideal before the update, nil if nothing can be learned.
+func (uc *SwipeUseCase) learnPreferences(ctx context.Context, swiperProfile *domain.Profile, swipedID int) (*domain.IdealSnapshot, error) {
+	// Get swiped user's measured traits
+	// If swiped user didn't take the test, we can't learn
+	swipedResult, err := uc.bigFiveRepo.GetByUserID(ctx, swipedID)
 	if err != nil {
-		return
-	}
-
-	// Get swiped user profile (to get their traits)
-	swipedProfile, err := uc.profileRepo.GetByUserID(ctx, swipedID)
-	if err != nil {
-		return
-	}
-
-	// If swiped user doesn't have traits set (e.g. didn't take test), we can't learn
-	if swipedProfile.PrefOpenness == nil {
-		return
+		if errors.Is(err, domain.ErrBigFiveNotFound) {
+			return nil, nil
+		}
+		return nil, err
 	}
+	target := swipedResult.Traits()
 
 	// Learning rate (how fast we adapt)
 	const learningRate = 0.1
 
-	// Helper to update a single trait
-	updateTrait := func(current *float64, target *float64) *float64 {
-		if current == nil {
-			// If not set, initialize with target
-			val := *target
-			return &val
-		}
-		if target == nil {
-			return current
-		}
+	before := &domain.IdealSnapshot{Traits: swiperProfile.IdealTraits()}
+
+	ideal := swiperProfile.IdealTraits()
+	if ideal == nil {
+		// If not set, initialize with target
+		ideal = target
+	} else {
 		// New = Old + LR * (Target - Old)
-		val := *current + learningRate*(*target-*current)
-		return &val
+		ideal.Openness += learningRate * (target.Openness - ideal.Openness)
+		ideal.Conscientiousness += learningRate * (target.Conscientiousness - ideal.Conscientiousness)
+		ideal.Extraversion += learningRate * (target.Extraversion - ideal.Extraversion)
+		ideal.Agreeableness += learningRate * (target.Agreeableness - ideal.Agreeableness)
+		ideal.Neuroticism += learningRate * (target.Neuroticism - ideal.Neuroticism)
 	}
 
 	// Update preferences
-	swiperProfile.PrefOpenness = updateTrait(swiperProfile.PrefOpenness, swipedProfile.PrefOpenness) // Using their actual traits as target
-	swiperProfile.PrefConscientiousness = updateTrait(swiperProfile.PrefConscientiousness, swipedProfile.PrefConscientiousness)
-	swiperProfile.PrefExtraversion = updateTrait(swiperProfile.PrefExtraversion, swipedProfile.PrefExtraversion)
-	swiperProfile.PrefAgreeableness = updateTrait(swiperProfile.PrefAgreeableness, swipedProfile.PrefAgreeableness)
-	swiperProfile.PrefNeuroticism = updateTrait(swiperProfile.PrefNeuroticism, swipedProfile.PrefNeuroticism)
-
-	// Save updated profile
-	_ = uc.profileRepo.Update(ctx, swiperProfile)
+	swiperProfile.SetIdealTraits(ideal)
+
+	return before, nil
 }
 
-func (uc *SwipeUseCase) enrichMatchWithAI(ctx context.Context, matchID, user1ID, user2ID int) {
-	fmt.Printf("🤖 [AI Wingman] Starting enrichMatchWithAI for match %d (users %d and %d)\n", matchID, user1ID, user2ID)
+// enrichMatchWithAI generates the match explanation and icebreakers for both users and saves them to the match.
+// Whatever was generated is saved, an error is returned if some of it failed so the job is retried.
+func (uc *SwipeUseCase) enrichMatchWithAI(ctx context.Context, match *domain.Match) error {
+	user1ID, user2ID := match.User1ID, match.User2ID
+	fmt.Printf("🤖 [AI Wingman] Starting enrichMatchWithAI for match %d (users %d and %d)\n", match.ID, user1ID, user2ID)
 
 	// Get profiles
 	p1, err := uc.profileRepo.GetByUserID(ctx, user1ID)
 	if err != nil {
-		fmt.Printf("❌ [AI Wingman] Failed to get profile for user %d: %v\n", user1ID, err)
-		return
+		return fmt.Errorf("failed to get profile for user %d: %w", user1ID, err)
 	}
 	p2, err := uc.profileRepo.GetByUserID(ctx, user2ID)
 	if err != nil {
-		fmt.Printf("❌ [AI Wingman] Failed to get profile for user %d: %v\n", user2ID, err)
-		return
+		return fmt.Errorf("failed to get profile for user %d: %w", user2ID, err)
 	}
 
 	fmt.Printf("✅ [AI Wingman] Got profiles: %s and %s\n", p1.DisplayName, p2.DisplayName)
 
-	// Prepare data for Gemini
-	traits1 := map[string]interface{}{
-		"Name":      p1.DisplayName,
-		"Interests": p1.Interests,
-		"Bio":       p1.Bio,
-	}
-	if p1.PrefOpenness != nil {
-		traits1["BigFive"] = map[string]float64{
-			"Openness":     *p1.PrefOpenness, // Using pref as proxy for self if self not separate
-			"Extraversion": *p1.PrefExtraversion,
-		}
+	// Prepare data for the AI Wingman
+	person1 := ai.NewPerson(p1, uc.getTraits(ctx, user1ID))
+	person2 := ai.NewPerson(p2, uc.getTraits(ctx, user2ID))
+
+	// The whole match is generated with one prompt version so versions can be compared
+	version := uc.wingman.PromptVersion(match.ID)
+
+	var genErr error
+
+	// Generate Explanation
+	fmt.Printf("🔮 [AI Wingman] Generating match explanation...\n")
+	explanation, err := uc.wingman.MatchExplanation(ctx, version, person1, person2)
+	if err == nil && explanation != "" {
+		// The explanation describes both users, only its length is logged
+		fmt.Printf("✨ AI Explanation for match %d: %d characters\n", match.ID, utf8.RuneCountInString(explanation))
+		match.Explanation = &explanation
+	} else if err != nil {
+		genErr = fmt.Errorf("failed to generate explanation: %w", err)
 	}
 
-	traits2 := map[string]interface{}{
-		"Name":      p2.DisplayName,
-		"Interests": p2.Interests,
-		"Bio":       p2.Bio,
+	// Generate Icebreakers for each user to send to the other
+	fmt.Printf("🔮 [AI Wingman] Generating icebreakers...\n")
+	icebreakers := &domain.Icebreakers{}
+	icebreakers.User1, err = uc.wingman.Icebreakers(ctx, version, person1, person2)
+	if err != nil {
+		genErr = fmt.Errorf("failed to generate icebreakers for user %d: %w", user1ID, err)
 	}
-	if p2.PrefOpenness != nil {
-		traits2["BigFive"] = map[string]float64{
-			"Openness":     *p2.PrefOpenness,
-			"Extraversion": *p2.PrefExtraversion,
-		}
+	icebreakers.User2, err = uc.wingman.Icebreakers(ctx, version, person2, person1)
+	if err != nil {
+		genErr = fmt.Errorf("failed to generate icebreakers for user %d: %w", user2ID, err)
 	}
-
-	// Generate Explanation
-	fmt.Printf("🔮 [AI Wingman] Calling Gemini for match explanation...\n")
-	explanation, err := uc.geminiClient.GenerateMatchExplanation(ctx, traits1, traits2)
-	if err == nil {
-		fmt.Printf("✨ AI Explanation: %s\n", explanation)
-	} else {
-		fmt.Printf("❌ [AI Wingman] Failed to generate explanation: %v\n", err)
+	if len(icebreakers.User1) > 0 || len(icebreakers.User2) > 0 {
+		// Icebreakers are private to each user, only their number is logged
+		fmt.Printf("✨ AI Icebreakers: %d / %d\n", len(icebreakers.User1), len(icebreakers.User2))
+		match.Icebreakers = icebreakers
 	}
 
-	// Generate Icebreakers (for User 1 to send to User 2)
-	fmt.Printf("🔮 [AI Wingman] Calling Gemini for icebreakers...\n")
-	icebreakers, err := uc.geminiClient.GenerateIcebreakers(ctx, p1.Interests, p2.Interests)
-	if err == nil {
-		fmt.Printf("✨ AI Icebreakers: %v\n", icebreakers)
-	} else {
-		fmt.Printf("❌ [AI Wingman] Failed to generate icebreakers: %v\n", err)
+	if match.Explanation != nil || match.Icebreakers != nil {
+		match.PromptVersion = &version
+		if err := uc.matchRepo.UpdateAIFields(ctx, match); err != nil {
+			return fmt.Errorf("failed to save AI content: %w", err)
+		}
+		fmt.Printf("✅ [AI Wingman] Saved AI content of match %d (prompts %s)\n", match.ID, version)
 	}
 
-	// TODO: Save to DB. I need to add UpdateAIFields to MatchRepository.
-	fmt.Printf("⚠️  [AI Wingman] Note: AI content not saved to DB (UpdateAIFields not implemented)\n")
+	return genErr
+}
+
+// getTraits returns the measured Big Five traits of the user, nil if the user didn't take the test
+func (uc *SwipeUseCase) getTraits(ctx context.Context, userID int) *domain.Traits {
+	result, err := uc.bigFiveRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil
+	}
+	return result.Traits()
 }
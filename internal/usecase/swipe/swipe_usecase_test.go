@@ -0,0 +1,129 @@
+package swipe
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/jobs"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/realtime"
+	"github.com/gdugdh24/mpit2026-backend/internal/repository/postgres"
+	"github.com/gdugdh24/mpit2026-backend/internal/repository/postgres/pgtest"
+)
+
+func TestCreateSwipeConcurrentMutualLike(t *testing.T) {
+	// Every pair races once, so a missing lock shows up as a lost or duplicated match
+	const pairs = 20
+
+	db := pgtest.Open(t)
+	ctx := context.Background()
+
+	var userIDs []int
+	err := db.Select(&userIDs, `
+		INSERT INTO users (vk_id, gender, birth_date)
+		SELECT 1000 + i, CASE WHEN i % 2 = 0 THEN 'male' ELSE 'female' END, DATE '2000-01-01'
+		FROM generate_series(1, $1) AS i
+		ORDER BY i
+		RETURNING id
+	`, 2*pairs)
+	if err != nil {
+		t.Fatalf("failed to seed users: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO profiles (user_id, display_name) SELECT id, 'User ' || id FROM users`); err != nil {
+		t.Fatalf("failed to seed profiles: %v", err)
+	}
+
+	broker := realtime.NewMemoryBroker()
+	uc := NewSwipeUseCase(
+		postgres.NewSwipeRepository(db),
+		postgres.NewMatchRepository(db),
+		postgres.NewProfileRepository(db),
+		postgres.NewUserRepository(db),
+		postgres.NewBigFiveRepository(db),
+		postgres.NewUnitOfWork(db),
+		// Jobs are only stored, no workers are started
+		jobs.NewQueue(postgres.NewJobRepository(db), jobs.Config{MaxAttempts: 1}),
+		nil,
+		realtime.NewPublisher(broker, nil),
+		nil,
+		nil,
+		UndoConfig{},
+		1,
+		nil,
+		nil,
+	)
+
+	events := make(map[int]<-chan *realtime.Event, len(userIDs))
+	for _, id := range userIDs {
+		ch, unsubscribe, err := broker.Subscribe(ctx, id)
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+		defer unsubscribe()
+		events[id] = ch
+	}
+
+	for i := 0; i < pairs; i++ {
+		a, b := userIDs[2*i], userIDs[2*i+1]
+
+		var (
+			wg        sync.WaitGroup
+			start     = make(chan struct{})
+			responses [2]*SwipeResponse
+			errs      [2]error
+		)
+		for j, swipe := range [][2]int{{a, b}, {b, a}} {
+			wg.Add(1)
+			go func(j, swiperID, swipedID int) {
+				defer wg.Done()
+				<-start
+				responses[j], errs[j] = uc.CreateSwipe(ctx, swiperID, &SwipeRequest{SwipedUserID: swipedID, IsLike: true})
+			}(j, swipe[0], swipe[1])
+		}
+		close(start)
+		wg.Wait()
+
+		reported := 0
+		for j := range responses {
+			if errs[j] != nil {
+				t.Fatalf("pair %d: CreateSwipe() error = %v", i, errs[j])
+			}
+			if responses[j].IsMatch {
+				reported++
+			}
+		}
+		if reported != 1 {
+			t.Fatalf("pair %d: %d responses report a match, want 1", i, reported)
+		}
+
+		var count int
+		if err := db.Get(&count, `SELECT COUNT(*) FROM matches WHERE is_active AND user1_id = $1 AND user2_id = $2`, a, b); err != nil {
+			t.Fatalf("failed to count matches: %v", err)
+		}
+		if count != 1 {
+			t.Fatalf("pair %d: %d matches stored, want 1", i, count)
+		}
+
+		for _, id := range []int{a, b} {
+			if got := countEvents(events[id], realtime.EventNewMatch); got != 1 {
+				t.Fatalf("pair %d: user %d got %d %s events, want 1", i, id, got, realtime.EventNewMatch)
+			}
+		}
+	}
+}
+
+// countEvents drains the events delivered so far and counts those of the type.
+// The publisher delivers synchronously, so all events of finished swipes are already buffered.
+func countEvents(ch <-chan *realtime.Event, eventType realtime.EventType) int {
+	count := 0
+	for {
+		select {
+		case event := <-ch:
+			if event.Type == eventType {
+				count++
+			}
+		default:
+			return count
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package swipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gdugdh24/mpit2026-backend/internal/domain"
+	"github.com/gdugdh24/mpit2026-backend/internal/infrastructure/realtime"
+)
+
+// UndoSwipe reverts the user's most recent swipe: the swiped user returns to the top of the deck,
+// preferences learned from the like are restored and a match created by it is deactivated.
+// The limit check, the rewind, the preference restore and the match deactivation run in one
+// transaction under the lock of the user's profile, so parallel undos can't exceed the limit
+// and preference learning can't interleave with the restore.
+func (uc *SwipeUseCase) UndoSwipe(ctx context.Context, userID int) (*UndoResponse, error) {
+	var (
+		response     *UndoResponse
+		removedMatch *domain.Match
+	)
+
+	err := uc.uow.Do(ctx, func(ctx context.Context) error {
+		response, removedMatch = nil, nil
+
+		// The same lock is taken by preference learning, see handleLearnPreferences
+		profile, err := uc.profileRepo.GetByUserIDForUpdate(ctx, userID)
+		if err != nil && !errors.Is(err, domain.ErrProfileNotFound) {
+			return fmt.Errorf("failed to lock profile: %w", err)
+		}
+
+		swipes, err := uc.swipeRepo.GetUserSwipes(ctx, userID, 1, 0)
+		if err != nil {
+			return fmt.Errorf("failed to get last swipe: %w", err)
+		}
+		if len(swipes) == 0 {
+			return domain.ErrSwipeNotFound
+		}
+		last := swipes[0]
+
+		if time.Since(last.CreatedAt) > uc.undo.Window {
+			return domain.ErrUndoWindowExpired
+		}
+
+		rewinds, err := uc.swipeRepo.CountRewindsSince(ctx, userID, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			return fmt.Errorf("failed to count rewinds: %w", err)
+		}
+		if rewinds >= uc.undo.DailyLimit {
+			return domain.ErrUndoLimitReached
+		}
+
+		if err := uc.swipeRepo.Rewind(ctx, last); err != nil {
+			return err
+		}
+
+		if last.IdealBefore != nil && profile != nil {
+			profile.SetIdealTraits(last.IdealBefore.Traits)
+			if err := uc.profileRepo.Update(ctx, profile); err != nil {
+				return fmt.Errorf("failed to restore preferences: %w", err)
+			}
+		}
+
+		if last.IsLike {
+			removedMatch, err = uc.deactivateMatch(ctx, userID, last.SwipedID)
+			if err != nil {
+				return fmt.Errorf("failed to remove match: %w", err)
+			}
+		}
+
+		response = &UndoResponse{
+			Swipe:        last,
+			MatchRemoved: removedMatch != nil,
+			RewindsLeft:  uc.undo.DailyLimit - rewinds - 1,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if removedMatch != nil {
+		uc.publisher.Publish(ctx, realtime.EventMatchRemoved, realtime.MatchRemovedPayload{
+			MatchID: removedMatch.ID,
+		}, removedMatch.User1ID, removedMatch.User2ID)
+	}
+
+	if err := uc.feedUseCase.Requeue(ctx, userID, response.Swipe.SwipedID); err != nil {
+		fmt.Printf("⚠️  [Swipe] Failed to requeue user %d in deck of user %d: %v\n", response.Swipe.SwipedID, userID, err)
+	}
+
+	return response, nil
+}
+
+// deactivateMatch deactivates the active match of the users, returns nil if there is none
+func (uc *SwipeUseCase) deactivateMatch(ctx context.Context, user1ID, user2ID int) (*domain.Match, error) {
+	m, err := uc.matchRepo.GetByUsers(ctx, user1ID, user2ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMatchNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !m.IsActive {
+		return nil, nil
+	}
+
+	if err := uc.matchRepo.Deactivate(ctx, m.ID, domain.MatchDeactivatedByUndo); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notifications_user_created;
+
+ALTER TABLE notifications
+DROP COLUMN IF EXISTS kind,
+DROP COLUMN IF EXISTS payload;
@@ -0,0 +1,6 @@
+-- Typed notifications with structured payload
+ALTER TABLE notifications
+ADD COLUMN kind VARCHAR(32) NOT NULL DEFAULT 'system',
+ADD COLUMN payload JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_notifications_user_created ON notifications(user_id, created_at DESC);
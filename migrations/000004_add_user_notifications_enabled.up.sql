@@ -0,0 +1,3 @@
+-- Whether the user allowed the Mini App to send push notifications (vk_are_notifications_enabled)
+ALTER TABLE users
+ADD COLUMN notifications_enabled BOOLEAN NOT NULL DEFAULT FALSE;
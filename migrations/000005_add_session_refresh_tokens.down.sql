@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_sessions_refresh_token_hash;
+
+ALTER TABLE sessions
+DROP COLUMN IF EXISTS refresh_token_hash,
+DROP COLUMN IF EXISTS refreshed_at;
@@ -0,0 +1,11 @@
+-- Rotating refresh tokens: the session keeps the hash of its current refresh token
+ALTER TABLE sessions
+ADD COLUMN refresh_token_hash VARCHAR(64),
+ADD COLUMN refreshed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX idx_sessions_refresh_token_hash ON sessions(refresh_token_hash);
+
+-- FORCED LOGOUT: old single 7-day tokens carry no session id and can't be refreshed,
+-- so the new columns can't be backfilled. All sessions are dropped and every user
+-- has to log in again through /auth/vk after this migration is deployed.
+DELETE FROM sessions;
@@ -0,0 +1,5 @@
+-- Last time the session was used, shown in the list of active sessions
+ALTER TABLE sessions
+ADD COLUMN last_seen_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE sessions SET last_seen_at = COALESCE(refreshed_at, created_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_profiles_feed_location;
+DROP INDEX IF EXISTS idx_users_gender_birth_date;
@@ -0,0 +1,6 @@
+-- Feed candidate query: gender + age (birth_date) filter on users
+CREATE INDEX IF NOT EXISTS idx_users_gender_birth_date ON users(gender, birth_date);
+
+-- Feed candidate query: bounding box on coordinates of completed profiles
+CREATE INDEX IF NOT EXISTS idx_profiles_feed_location ON profiles(location_lat, location_lon)
+WHERE is_onboarding_complete = TRUE;
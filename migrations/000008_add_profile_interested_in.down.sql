@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_profiles_interested_in;
+
+ALTER TABLE profiles
+DROP CONSTRAINT IF EXISTS profiles_interested_in_check,
+DROP COLUMN IF EXISTS interested_in;
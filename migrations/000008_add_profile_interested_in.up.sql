@@ -0,0 +1,16 @@
+-- Genders the user wants to see in the feed, a candidate is shown only if both sides match
+ALTER TABLE profiles
+ADD COLUMN interested_in VARCHAR(10)[];
+
+-- Keep the previous behaviour for existing profiles: interested in the opposite gender
+UPDATE profiles p
+SET interested_in = ARRAY[CASE u.gender WHEN 'male' THEN 'female' ELSE 'male' END]::VARCHAR(10)[]
+FROM users u
+WHERE u.id = p.user_id;
+
+ALTER TABLE profiles
+ALTER COLUMN interested_in SET NOT NULL,
+ADD CONSTRAINT profiles_interested_in_check
+    CHECK (cardinality(interested_in) >= 1 AND interested_in <@ ARRAY['male', 'female']::VARCHAR(10)[]);
+
+CREATE INDEX idx_profiles_interested_in ON profiles USING GIN(interested_in);
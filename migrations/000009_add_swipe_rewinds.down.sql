@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS swipe_rewinds;
+
+ALTER TABLE swipes
+DROP COLUMN IF EXISTS ideal_before;
@@ -0,0 +1,15 @@
+-- "Ideal partner" personality of the swiper before it was learned from the like,
+-- NULL if the swipe didn't change it
+ALTER TABLE swipes
+ADD COLUMN ideal_before JSONB;
+
+-- Undone swipes, rewinds per day are limited
+CREATE TABLE swipe_rewinds (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    swiped_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    was_like BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_swipe_rewinds_user_created ON swipe_rewinds(user_id, created_at DESC);
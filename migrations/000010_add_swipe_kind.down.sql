@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS idx_swipes_super_likes;
+DROP INDEX IF EXISTS idx_swipes_swiped_id;
+
+ALTER TABLE swipes
+DROP COLUMN IF EXISTS is_like;
+
+ALTER TABLE swipes
+ADD COLUMN is_like BOOLEAN;
+
+UPDATE swipes
+SET is_like = (kind <> 'dislike');
+
+ALTER TABLE swipes
+ALTER COLUMN is_like SET NOT NULL,
+DROP CONSTRAINT IF EXISTS swipes_kind_check,
+DROP COLUMN IF EXISTS kind;
+
+CREATE INDEX idx_swipes_swiped_id ON swipes(swiped_id, is_like);
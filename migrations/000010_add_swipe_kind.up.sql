@@ -0,0 +1,24 @@
+-- Swipe kind replaces the is_like flag: like, dislike or super_like
+ALTER TABLE swipes
+ADD COLUMN kind VARCHAR(10);
+
+UPDATE swipes
+SET kind = CASE WHEN is_like THEN 'like' ELSE 'dislike' END;
+
+ALTER TABLE swipes
+ALTER COLUMN kind SET NOT NULL,
+ADD CONSTRAINT swipes_kind_check CHECK (kind IN ('like', 'dislike', 'super_like'));
+
+-- is_like is derived from kind so mutual like checks keep working for super likes
+DROP INDEX IF EXISTS idx_swipes_swiped_id;
+
+ALTER TABLE swipes
+DROP COLUMN is_like;
+
+ALTER TABLE swipes
+ADD COLUMN is_like BOOLEAN GENERATED ALWAYS AS (kind <> 'dislike') STORED;
+
+CREATE INDEX idx_swipes_swiped_id ON swipes(swiped_id, is_like);
+
+-- Daily super like limit
+CREATE INDEX idx_swipes_super_likes ON swipes(swiper_id, created_at DESC) WHERE kind = 'super_like';
@@ -0,0 +1,3 @@
+-- Set when the user likes almost everyone they swipe, such users are demoted in the feed
+ALTER TABLE users
+ADD COLUMN mass_liker_flagged_at TIMESTAMP WITH TIME ZONE;
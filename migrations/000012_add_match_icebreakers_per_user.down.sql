@@ -0,0 +1,5 @@
+ALTER TABLE matches
+DROP COLUMN IF EXISTS icebreakers;
+
+ALTER TABLE matches
+ADD COLUMN icebreakers TEXT[];
@@ -0,0 +1,6 @@
+-- Icebreakers are generated for both sides of the match, the TEXT[] column was never written
+ALTER TABLE matches
+DROP COLUMN IF EXISTS icebreakers;
+
+ALTER TABLE matches
+ADD COLUMN icebreakers JSONB;
@@ -0,0 +1,3 @@
+-- Prompt version the AI content of the match was generated with, used to compare prompts
+ALTER TABLE matches
+ADD COLUMN ai_prompt_version VARCHAR(50);
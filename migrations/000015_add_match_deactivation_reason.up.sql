@@ -0,0 +1,8 @@
+-- Why an inactive match was deactivated: a mutual like restores matches removed by undo,
+-- but never ones removed by a user
+ALTER TABLE matches
+ADD COLUMN deactivation_reason VARCHAR(20)
+CHECK (deactivation_reason IN ('undo', 'unmatch'));
+
+-- The reason of existing inactive matches is unknown, treat them as removed by a user
+UPDATE matches SET deactivation_reason = 'unmatch' WHERE is_active = false;
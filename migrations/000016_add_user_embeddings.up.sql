@@ -0,0 +1,14 @@
+-- Interest embeddings used by the feed "embedding" component.
+-- The pgvector version of this table in 000001 is commented out, so vectors are plain
+-- REAL arrays here: similarity is computed by the application, no vector index is needed.
+CREATE TABLE IF NOT EXISTS user_embeddings (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    music_vector REAL[],
+    groups_vector REAL[],
+    posts_vector REAL[],
+    combined_vector REAL[],
+    vk_data_fetched_at TIMESTAMP WITH TIME ZONE,
+    vectors_updated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
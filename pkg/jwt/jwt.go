@@ -13,10 +13,19 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+// Token types put into the typ claim
+const (
+	TypeAccess  = "access"
+	TypeRefresh = "refresh"
+)
+
 // Claims represents JWT claims
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Role   string    `json:"role"`
+	UserID    int    `json:"user_id"`
+	SessionID int    `json:"sid"`
+	Role      string `json:"role"`
+	// Type tells access and refresh tokens apart even if their secrets match
+	Type string `json:"typ"`
 	jwt.RegisteredClaims
 }
 
@@ -38,11 +47,23 @@ func NewTokenManager(accessSecret, refreshSecret string, accessExpiryMin, refres
 	}
 }
 
+// AccessExpiry returns the lifetime of access tokens
+func (tm *TokenManager) AccessExpiry() time.Duration {
+	return tm.accessExpiry
+}
+
+// RefreshExpiry returns the lifetime of refresh tokens
+func (tm *TokenManager) RefreshExpiry() time.Duration {
+	return tm.refreshExpiry
+}
+
 // GenerateAccessToken generates a new access token
-func (tm *TokenManager) GenerateAccessToken(userID uuid.UUID, role string) (string, error) {
+func (tm *TokenManager) GenerateAccessToken(userID, sessionID int, role string) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		SessionID: sessionID,
+		Role:      role,
+		Type:      TypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tm.accessExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -53,12 +74,16 @@ func (tm *TokenManager) GenerateAccessToken(userID uuid.UUID, role string) (stri
 	return token.SignedString([]byte(tm.accessSecret))
 }
 
-// GenerateRefreshToken generates a new refresh token
-func (tm *TokenManager) GenerateRefreshToken(userID uuid.UUID, role string) (string, error) {
+// GenerateRefreshToken generates a new refresh token.
+// Every token gets a unique ID so rotated tokens never repeat.
+func (tm *TokenManager) GenerateRefreshToken(userID, sessionID int, role string) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		SessionID: sessionID,
+		Role:      role,
+		Type:      TypeRefresh,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tm.refreshExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
@@ -70,16 +95,16 @@ func (tm *TokenManager) GenerateRefreshToken(userID uuid.UUID, role string) (str
 
 // ValidateAccessToken validates an access token and returns claims
 func (tm *TokenManager) ValidateAccessToken(tokenString string) (*Claims, error) {
-	return tm.validateToken(tokenString, tm.accessSecret)
+	return tm.validateToken(tokenString, tm.accessSecret, TypeAccess)
 }
 
 // ValidateRefreshToken validates a refresh token and returns claims
 func (tm *TokenManager) ValidateRefreshToken(tokenString string) (*Claims, error) {
-	return tm.validateToken(tokenString, tm.refreshSecret)
+	return tm.validateToken(tokenString, tm.refreshSecret, TypeRefresh)
 }
 
-// validateToken validates a token with the given secret
-func (tm *TokenManager) validateToken(tokenString, secret string) (*Claims, error) {
+// validateToken validates a token of the given type with the given secret
+func (tm *TokenManager) validateToken(tokenString, secret, tokenType string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, ErrInvalidToken
@@ -95,7 +120,7 @@ func (tm *TokenManager) validateToken(tokenString, secret string) (*Claims, erro
 	}
 
 	claims, ok := token.Claims.(*Claims)
-	if !ok || !token.Valid {
+	if !ok || !token.Valid || claims.Type != tokenType {
 		return nil, ErrInvalidToken
 	}
 
@@ -104,15 +129,15 @@ func (tm *TokenManager) validateToken(tokenString, secret string) (*Claims, erro
 
 // ExtractUserID extracts user ID from access token without validation
 // Use only for non-critical operations
-func (tm *TokenManager) ExtractUserID(tokenString string) (uuid.UUID, error) {
+func (tm *TokenManager) ExtractUserID(tokenString string) (int, error) {
 	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
 	if err != nil {
-		return uuid.Nil, err
+		return 0, err
 	}
 
 	claims, ok := token.Claims.(*Claims)
 	if !ok {
-		return uuid.Nil, ErrInvalidToken
+		return 0, ErrInvalidToken
 	}
 
 	return claims.UserID, nil
@@ -0,0 +1,31 @@
+package jwt
+
+import "testing"
+
+func TestTokenTypesAreNotInterchangeable(t *testing.T) {
+	// Same secret for both types, only the typ claim tells them apart
+	secret := "0123456789abcdef0123456789abcdef"
+	tm := NewTokenManager(secret, secret, 15, 30)
+
+	access, err := tm.GenerateAccessToken(1, 2, "user")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+	refresh, err := tm.GenerateRefreshToken(1, 2, "user")
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken() error = %v", err)
+	}
+
+	if _, err := tm.ValidateAccessToken(access); err != nil {
+		t.Errorf("ValidateAccessToken(access) error = %v", err)
+	}
+	if _, err := tm.ValidateRefreshToken(refresh); err != nil {
+		t.Errorf("ValidateRefreshToken(refresh) error = %v", err)
+	}
+	if _, err := tm.ValidateAccessToken(refresh); err != ErrInvalidToken {
+		t.Errorf("ValidateAccessToken(refresh) error = %v, want %v", err, ErrInvalidToken)
+	}
+	if _, err := tm.ValidateRefreshToken(access); err != ErrInvalidToken {
+		t.Errorf("ValidateRefreshToken(access) error = %v, want %v", err, ErrInvalidToken)
+	}
+}
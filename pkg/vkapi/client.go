@@ -5,25 +5,42 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
 const (
-	apiVersion = "5.131"
-	apiBaseURL = "https://api.vk.com/method"
+	apiVersion     = "5.131"
+	defaultBaseURL = "https://api.vk.com/method"
 )
 
 // Client represents VK API client
 type Client struct {
 	httpClient *http.Client
+	baseURL    string
+	limiter    *rateLimiter
+	// retryBackoff is the delay before the first retry of a failed call
+	retryBackoff time.Duration
 }
 
 // NewClient creates new VK API client
 func NewClient() *Client {
+	return NewClientWithBaseURL(defaultBaseURL)
+}
+
+// NewClientWithBaseURL creates new VK API client for the given API endpoint
+// (e.g. a local fake server in tests). Empty baseURL means the real VK API.
+func NewClientWithBaseURL(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
 	return &Client{
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		limiter:      newRateLimiter(requestsPerSecond),
+		retryBackoff: retryBackoff,
 	}
 }
 
@@ -59,7 +76,7 @@ func (c *Client) GetUserInfo(accessToken string, userID int) (*VKUserInfo, error
 	params.Set("access_token", accessToken)
 	params.Set("v", apiVersion)
 
-	apiURL := fmt.Sprintf("%s/users.get?%s", apiBaseURL, params.Encode())
+	apiURL := fmt.Sprintf("%s/users.get?%s", c.baseURL, params.Encode())
 
 	resp, err := c.httpClient.Get(apiURL)
 	if err != nil {
@@ -95,7 +112,7 @@ func (c *Client) GetUserWall(accessToken string, userID int, count int) ([]map[s
 	params.Set("access_token", accessToken)
 	params.Set("v", apiVersion)
 
-	apiURL := fmt.Sprintf("%s/wall.get?%s", apiBaseURL, params.Encode())
+	apiURL := fmt.Sprintf("%s/wall.get?%s", c.baseURL, params.Encode())
 
 	resp, err := c.httpClient.Get(apiURL)
 	if err != nil {
@@ -140,7 +157,7 @@ func (c *Client) GetUserGroups(accessToken string, userID int) ([]map[string]int
 	params.Set("access_token", accessToken)
 	params.Set("v", apiVersion)
 
-	apiURL := fmt.Sprintf("%s/groups.get?%s", apiBaseURL, params.Encode())
+	apiURL := fmt.Sprintf("%s/groups.get?%s", c.baseURL, params.Encode())
 
 	resp, err := c.httpClient.Get(apiURL)
 	if err != nil {
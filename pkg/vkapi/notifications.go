@@ -0,0 +1,206 @@
+package vkapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxNotificationRecipients is the max number of user ids per notifications.sendMessage call
+	MaxNotificationRecipients = 100
+	// MaxNotificationLength is the max notification text length
+	MaxNotificationLength = 254
+
+	// requestsPerSecond is the VK API limit for calls with a service token
+	requestsPerSecond = 3
+	// maxRetries is the number of retries of a call failed with a retryable error
+	maxRetries = 3
+	// retryBackoff is the delay before the first retry, doubled on each attempt
+	retryBackoff = 500 * time.Millisecond
+)
+
+// VK API error codes that are worth retrying
+const (
+	ErrCodeTooManyRequests = 6
+	ErrCodeFloodControl    = 9
+	ErrCodeInternal        = 10
+)
+
+// APIError is an error returned by VK API
+type APIError struct {
+	Code    int    `json:"error_code"`
+	Message string `json:"error_msg"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("VK API error %d: %s", e.Code, e.Message)
+}
+
+// Retryable reports whether the call may succeed if repeated later
+func (e *APIError) Retryable() bool {
+	switch e.Code {
+	case ErrCodeTooManyRequests, ErrCodeFloodControl, ErrCodeInternal:
+		return true
+	}
+	return false
+}
+
+// NotificationResult is the delivery status of a notification to a single user
+type NotificationResult struct {
+	UserID int  `json:"user_id"`
+	Status bool `json:"status"`
+	Error  *struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"error,omitempty"`
+}
+
+// notificationsResponse represents notifications.sendMessage response
+type notificationsResponse struct {
+	Response []NotificationResult `json:"response"`
+	Error    *APIError            `json:"error"`
+}
+
+// SendNotification sends a Mini App push notification to VK users via notifications.sendMessage.
+// accessToken must be the service token of the app. Recipients are split into batches of
+// MaxNotificationRecipients; fragment is the URL hash the app is opened with.
+func (c *Client) SendNotification(ctx context.Context, accessToken string, vkUserIDs []int, message, fragment string) ([]NotificationResult, error) {
+	if len([]rune(message)) > MaxNotificationLength {
+		message = string([]rune(message)[:MaxNotificationLength])
+	}
+
+	results := make([]NotificationResult, 0, len(vkUserIDs))
+	for start := 0; start < len(vkUserIDs); start += MaxNotificationRecipients {
+		end := start + MaxNotificationRecipients
+		if end > len(vkUserIDs) {
+			end = len(vkUserIDs)
+		}
+
+		batch, err := c.sendNotificationBatch(ctx, accessToken, vkUserIDs[start:end], message, fragment)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, batch...)
+	}
+
+	return results, nil
+}
+
+// sendNotificationBatch calls notifications.sendMessage retrying on rate limit and internal errors
+func (c *Client) sendNotificationBatch(ctx context.Context, accessToken string, vkUserIDs []int, message, fragment string) ([]NotificationResult, error) {
+	ids := make([]string, len(vkUserIDs))
+	for i, id := range vkUserIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+
+	params := url.Values{}
+	params.Set("user_ids", strings.Join(ids, ","))
+	params.Set("message", message)
+	if fragment != "" {
+		params.Set("fragment", fragment)
+	}
+	params.Set("access_token", accessToken)
+	params.Set("v", apiVersion)
+
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		results, err := c.callNotifications(ctx, params)
+		if err == nil {
+			return results, nil
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.Retryable() || attempt >= maxRetries {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// callNotifications performs a single rate limited notifications.sendMessage call
+func (c *Client) callNotifications(ctx context.Context, params url.Values) ([]NotificationResult, error) {
+	if err := c.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	apiURL := fmt.Sprintf("%s/notifications.sendMessage", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call VK API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("VK API returned status %d", resp.StatusCode)
+	}
+
+	var apiResp notificationsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode VK API response: %w", err)
+	}
+
+	if apiResp.Error != nil {
+		return nil, apiResp.Error
+	}
+
+	return apiResp.Response, nil
+}
+
+// rateLimiter spaces out calls to stay within the VK API requests per second limit
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(perSecond int) *rateLimiter {
+	return &rateLimiter{
+		interval: time.Second / time.Duration(perSecond),
+	}
+}
+
+// wait blocks until the next call is allowed
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
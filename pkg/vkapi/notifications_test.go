@@ -0,0 +1,182 @@
+package vkapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeVK is a notifications.sendMessage endpoint that records calls
+// and fails the first calls with the given VK error codes
+type fakeVK struct {
+	mu       sync.Mutex
+	failWith []int
+	calls    []sentNotification
+	times    []time.Time
+}
+
+// sentNotification is a recorded call
+type sentNotification struct {
+	userIDs  []string
+	message  string
+	fragment string
+	token    string
+}
+
+func newFakeVK(t *testing.T, failWith ...int) (*fakeVK, *Client) {
+	t.Helper()
+	fake := &fakeVK{failWith: failWith}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client := NewClientWithBaseURL(server.URL + "/")
+	client.retryBackoff = time.Millisecond
+	return fake, client
+}
+
+func (f *fakeVK) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/notifications.sendMessage" || r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+	_ = r.ParseForm()
+
+	f.mu.Lock()
+	f.calls = append(f.calls, sentNotification{
+		userIDs:  strings.Split(r.PostForm.Get("user_ids"), ","),
+		message:  r.PostForm.Get("message"),
+		fragment: r.PostForm.Get("fragment"),
+		token:    r.PostForm.Get("access_token"),
+	})
+	f.times = append(f.times, time.Now())
+	var code int
+	if len(f.failWith) > 0 {
+		code, f.failWith = f.failWith[0], f.failWith[1:]
+	}
+	f.mu.Unlock()
+
+	if code != 0 {
+		_ = json.NewEncoder(w).Encode(notificationsResponse{Error: &APIError{Code: code, Message: "error"}})
+		return
+	}
+
+	var resp notificationsResponse
+	for _, id := range strings.Split(r.PostForm.Get("user_ids"), ",") {
+		userID, _ := strconv.Atoi(id)
+		resp.Response = append(resp.Response, NotificationResult{UserID: userID, Status: true})
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func userIDs(n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	return ids
+}
+
+func TestSendNotificationBatches(t *testing.T) {
+	fake, client := newFakeVK(t)
+
+	results, err := client.SendNotification(context.Background(), "service", userIDs(250), strings.Repeat("я", MaxNotificationLength+10), "match/1")
+	if err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+	if len(results) != 250 || results[0].UserID != 1 || results[249].UserID != 250 {
+		t.Fatalf("SendNotification() returned %d results", len(results))
+	}
+
+	wantSizes := []int{100, 100, 50}
+	if len(fake.calls) != len(wantSizes) {
+		t.Fatalf("made %d calls, want %d", len(fake.calls), len(wantSizes))
+	}
+	for i, call := range fake.calls {
+		if len(call.userIDs) != wantSizes[i] {
+			t.Errorf("call %d has %d recipients, want %d", i, len(call.userIDs), wantSizes[i])
+		}
+		if n := len([]rune(call.message)); n != MaxNotificationLength {
+			t.Errorf("call %d message has %d characters, want %d", i, n, MaxNotificationLength)
+		}
+		if call.fragment != "match/1" || call.token != "service" {
+			t.Errorf("call %d fragment %q, token %q", i, call.fragment, call.token)
+		}
+	}
+}
+
+func TestSendNotificationRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		failWith  []int
+		wantCalls int
+		wantCode  int
+	}{
+		{name: "too many requests", failWith: []int{ErrCodeTooManyRequests}, wantCalls: 2},
+		{name: "flood control", failWith: []int{ErrCodeFloodControl, ErrCodeFloodControl}, wantCalls: 3},
+		{name: "internal error", failWith: []int{ErrCodeInternal}, wantCalls: 2},
+		{
+			name:      "retries exhausted",
+			failWith:  []int{ErrCodeInternal, ErrCodeInternal, ErrCodeInternal, ErrCodeInternal},
+			wantCalls: maxRetries + 1,
+			wantCode:  ErrCodeInternal,
+		},
+		{name: "not retryable", failWith: []int{5}, wantCalls: 1, wantCode: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, client := newFakeVK(t, tt.failWith...)
+
+			_, err := client.SendNotification(context.Background(), "service", userIDs(3), "hi", "")
+			if tt.wantCode == 0 && err != nil {
+				t.Fatalf("SendNotification() error = %v", err)
+			}
+			if tt.wantCode != 0 {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.Code != tt.wantCode {
+					t.Fatalf("SendNotification() error = %v, want VK API error %d", err, tt.wantCode)
+				}
+			}
+			if len(fake.calls) != tt.wantCalls {
+				t.Fatalf("made %d calls, want %d", len(fake.calls), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestSendNotificationRateLimit(t *testing.T) {
+	fake, client := newFakeVK(t)
+
+	// 4 batches need 3 intervals between the calls
+	if _, err := client.SendNotification(context.Background(), "service", userIDs(4*MaxNotificationRecipients), "hi", ""); err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+
+	interval := time.Second / requestsPerSecond
+	for i := 1; i < len(fake.times); i++ {
+		// The server sees the calls with some jitter
+		if gap := fake.times[i].Sub(fake.times[i-1]); gap < interval-20*time.Millisecond {
+			t.Fatalf("calls %d and %d are %v apart, want at least %v", i-1, i, gap, interval)
+		}
+	}
+}
+
+func TestRateLimiterWaitHonorsContext(t *testing.T) {
+	limiter := newRateLimiter(1)
+	if err := limiter.wait(context.Background()); err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,28 @@
+-- Fake users for local development.
+-- Log in as any of them with dev auth: POST /api/v1/auth/dev/login {"user_id": <id>}
+-- VK IDs 900000001+ are reserved for seeded users.
+
+INSERT INTO users (vk_id, gender, birth_date, is_verified, is_online)
+VALUES
+    (900000001, 'female', '1998-04-12', true, false),
+    (900000002, 'male',   '1996-09-30', true, false),
+    (900000003, 'female', '2000-01-21', false, false),
+    (900000004, 'male',   '1994-06-05', false, false),
+    (900000005, 'female', '1999-11-17', true, false),
+    (900000006, 'male',   '2001-02-08', false, false)
+ON CONFLICT (vk_id) DO NOTHING;
+
+INSERT INTO profiles (user_id, display_name, bio, city, interests, location_lat, location_lon,
+                      pref_min_age, pref_max_age, pref_max_distance_km, is_onboarding_complete, interested_in)
+SELECT u.id, p.display_name, p.bio, p.city, p.interests, p.lat, p.lon, 18, 40, 50, true,
+       ARRAY[CASE u.gender WHEN 'male' THEN 'female' ELSE 'male' END]::VARCHAR(10)[]
+FROM (VALUES
+    (900000001, 'Анна',    'Люблю горы и кофе',          'Москва', ARRAY['travel', 'coffee', 'hiking'],   55.7558, 37.6173),
+    (900000002, 'Иван',    'Разработчик, играю на гитаре', 'Москва', ARRAY['music', 'it', 'guitar'],       55.7512, 37.6184),
+    (900000003, 'Мария',   'Фотографирую город',         'Москва', ARRAY['photo', 'art', 'travel'],        55.7601, 37.6250),
+    (900000004, 'Дмитрий', 'Бегаю марафоны',             'Москва', ARRAY['sport', 'running', 'books'],     55.7420, 37.6050),
+    (900000005, 'Екатерина', 'Читаю и готовлю',          'Москва', ARRAY['books', 'cooking', 'movies'],    55.7700, 37.5900),
+    (900000006, 'Алексей', 'Настолки и кино',            'Москва', ARRAY['boardgames', 'movies', 'it'],    55.7300, 37.6400)
+) AS p(vk_id, display_name, bio, city, interests, lat, lon)
+JOIN users u ON u.vk_id = p.vk_id
+ON CONFLICT (user_id) DO NOTHING;